@@ -0,0 +1,132 @@
+package lru
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+)
+
+// WithSecondChanceAdmission keeps keys that are only added once, such as
+// those of a scan, out of the cache. The first time a new key is added, it
+// is only recorded in a bloom filter sized for expectedKeys keys with a
+// false positive rate of fpRate, and is not cached; it is cached when added
+// again while recorded. Keys already in the cache are updated as usual.
+// Filtered keys are simply not cached: Add, ContainsOrAdd and PeekOrAdd
+// store nothing and report no eviction. FilteredAdds counts them.
+//
+// hash must spread the keys over 64 bits, as the bloom filter derives all
+// of its probes from it. A false positive admits a key on its first add,
+// which is harmless. To keep the false positive rate from growing, the
+// filter is cleared when a new key is recorded once expectedKeys keys
+// already have been, after which previously recorded keys need two adds
+// again; the key whose add cleared the filter is recorded afresh, so its
+// next add caches it.
+//
+// The filter takes about 1.2 bytes per expected key at a 1% false positive
+// rate. hash runs inside the critical section of every adding operation,
+// so it must be fast and must not call methods of the cache.
+func WithSecondChanceAdmission[Key comparable, Value any](expectedKeys int, fpRate float64, hash func(Key) uint64) Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		if expectedKeys <= 0 {
+			return fmt.Errorf("must provide a positive number of expected keys")
+		}
+		if !(fpRate > 0 && fpRate < 1) {
+			return fmt.Errorf("false positive rate must be between 0 and 1")
+		}
+		if hash == nil {
+			return fmt.Errorf("must provide a hash function")
+		}
+		c.admission = newDoorkeeper(expectedKeys, fpRate, hash)
+		return nil
+	}
+}
+
+// doorkeeper is a bloom filter recording the keys added once.
+type doorkeeper[Key comparable] struct {
+	// filtered is accessed atomically and kept first for 64-bit alignment.
+	filtered uint64
+
+	hash     func(Key) uint64
+	bits     []uint64
+	nbits    uint64
+	probes   int
+	recorded int
+	capacity int
+}
+
+// newDoorkeeper sizes the bloom filter for n keys at the false positive rate
+// p, using the optimal number of bits, -n*ln(p)/ln(2)^2, and of probes,
+// bits/n*ln(2).
+func newDoorkeeper[Key comparable](n int, p float64, hash func(Key) uint64) *doorkeeper[Key] {
+	nbits := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	if nbits < 64 {
+		nbits = 64
+	}
+	probes := int(math.Round(float64(nbits) / float64(n) * math.Ln2))
+	if probes < 1 {
+		probes = 1
+	}
+	return &doorkeeper[Key]{
+		hash:     hash,
+		bits:     make([]uint64, (nbits+63)/64),
+		nbits:    nbits,
+		probes:   probes,
+		capacity: n,
+	}
+}
+
+// admit reports whether the key was already recorded, and records it
+// otherwise.
+func (d *doorkeeper[Key]) admit(key Key) bool {
+	// double hashing, with the second hash derived from the first by the
+	// splitmix64 finalizer, and made odd so that the probes never repeat
+	h1 := d.hash(key)
+	h2 := h1
+	h2 = (h2 ^ (h2 >> 30)) * 0xbf58476d1ce4e5b9
+	h2 = (h2 ^ (h2 >> 27)) * 0x94d049bb133111eb
+	h2 = (h2 ^ (h2 >> 31)) | 1
+
+	seen := true
+	for i := 0; i < d.probes && seen; i++ {
+		bit := (h1 + uint64(i)*h2) % d.nbits
+		seen = d.bits[bit/64]&(1<<(bit%64)) != 0
+	}
+	if seen {
+		return true
+	}
+	atomic.AddUint64(&d.filtered, 1)
+	if d.recorded >= d.capacity {
+		for i := range d.bits {
+			d.bits[i] = 0
+		}
+		d.recorded = 0
+	}
+	for i := 0; i < d.probes; i++ {
+		bit := (h1 + uint64(i)*h2) % d.nbits
+		d.bits[bit/64] |= 1 << (bit % 64)
+	}
+	d.recorded++
+	return false
+}
+
+// admits reports whether a key being added must be cached. The caller must
+// hold the lock.
+func (c *Cache[Key, Value]) admits(key Key) bool {
+	if c.admission == nil {
+		return true
+	}
+	if _, ok := c.lookup(key); ok {
+		return true
+	}
+	return c.admission.admit(key)
+}
+
+// FilteredAdds returns the number of adds of new keys that were not cached
+// because it was the first time the key was added. It is always 0 unless
+// the cache was created with WithSecondChanceAdmission.
+func (c *Cache[Key, Value]) FilteredAdds() uint64 {
+	if c.admission == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&c.admission.filtered)
+}
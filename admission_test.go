@@ -0,0 +1,86 @@
+package lru
+
+import "testing"
+
+func testHash(k int) uint64 {
+	h := uint64(k) * 0x9e3779b97f4a7c15
+	return h ^ (h >> 32)
+}
+
+func TestWithSecondChanceAdmission(t *testing.T) {
+	l, err := NewWithOptions(8, nil, WithSecondChanceAdmission[int, int](100, 0.01, testHash))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// seen once
+	l.Add(1, 1)
+	if l.Contains(1) {
+		t.Fatalf("should not cache a key seen once")
+	}
+	if n := l.FilteredAdds(); n != 1 {
+		t.Fatalf("bad filtered count: %v", n)
+	}
+
+	// seen twice
+	l.Add(1, 2)
+	if v, ok := l.Get(1); !ok || v != 2 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+
+	// cached keys are updated as usual
+	l.Add(1, 3)
+	if v, _ := l.Peek(1); v != 3 {
+		t.Fatalf("bad value: %v", v)
+	}
+
+	// a scan stays out of the cache
+	for i := 100; i < 150; i++ {
+		l.Add(i, i)
+	}
+	if l.Len() > 2 || !l.Contains(1) {
+		t.Fatalf("scan should not flush the cache: %v", l.Keys())
+	}
+	if n := l.FilteredAdds(); n < 49 {
+		t.Fatalf("bad filtered count: %v", n)
+	}
+}
+
+func TestSecondChanceAdmission_Reset(t *testing.T) {
+	l, err := NewWithOptions(8, nil, WithSecondChanceAdmission[int, int](4, 0.01, testHash))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		l.Add(i, i)
+	}
+	if l.admission.recorded != 4 {
+		t.Fatalf("bad recorded: %v", l.admission.recorded)
+	}
+
+	// the fifth key clears the filter and is recorded afresh
+	l.Add(4, 4)
+	if l.admission.recorded != 1 {
+		t.Fatalf("filter should be cleared")
+	}
+	l.Add(4, 4)
+	if !l.Contains(4) {
+		t.Fatalf("should cache the key recorded by the reset")
+	}
+	l.Add(0, 0)
+	if l.Contains(0) {
+		t.Fatalf("should not cache a key recorded before the reset")
+	}
+}
+
+func TestSecondChanceAdmission_Invalid(t *testing.T) {
+	if _, err := NewWithOptions(8, nil, WithSecondChanceAdmission[int, int](0, 0.01, testHash)); err == nil {
+		t.Fatalf("should fail with no expected keys")
+	}
+	if _, err := NewWithOptions(8, nil, WithSecondChanceAdmission[int, int](10, 1, testHash)); err == nil {
+		t.Fatalf("should fail with invalid false positive rate")
+	}
+	if _, err := NewWithOptions(8, nil, WithSecondChanceAdmission[int, int](10, 0.01, nil)); err == nil {
+		t.Fatalf("should fail without hash")
+	}
+}
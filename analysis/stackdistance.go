@@ -0,0 +1,50 @@
+// Package analysis provides offline tools for analyzing cache workloads.
+package analysis
+
+// StackDistances returns the LRU stack distance, also known as the reuse
+// distance, of each access in the trace: the number of distinct keys
+// accessed since the previous access to the same key, or -1 if the key was
+// not accessed before.
+//
+// Following Mattson et al., an access is a hit in an LRU cache of size s if
+// and only if its stack distance is smaller than s, so the histogram of the
+// distances yields the hit ratio of every cache size in a single pass over
+// the trace.
+//
+// Rather than maintaining the LRU stack itself, which costs O(n·m) time for
+// n accesses to m distinct keys, each key is marked at the position of its
+// latest access in a Fenwick tree over the trace. The distance of an access
+// is the number of marks between it and the previous access to the same
+// key, so the whole trace is processed in O(n log n) time and O(n + m)
+// memory.
+func StackDistances[Key comparable](trace []Key) []int {
+	distances := make([]int, len(trace))
+	tree := make([]int, len(trace)+1)
+	last := make(map[Key]int)
+
+	// add adds delta to the mark at position i, 0-based.
+	add := func(i, delta int) {
+		for i++; i < len(tree); i += i & -i {
+			tree[i] += delta
+		}
+	}
+	// sum returns the number of marks at positions 0..i-1.
+	sum := func(i int) (s int) {
+		for ; i > 0; i -= i & -i {
+			s += tree[i]
+		}
+		return s
+	}
+
+	for i, key := range trace {
+		if p, ok := last[key]; ok {
+			distances[i] = sum(i) - sum(p+1)
+			add(p, -1)
+		} else {
+			distances[i] = -1
+		}
+		add(i, 1)
+		last[key] = i
+	}
+	return distances
+}
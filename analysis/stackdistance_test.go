@@ -0,0 +1,51 @@
+package analysis
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/errorhandler/golang-lru/simplelru"
+)
+
+func TestStackDistances(t *testing.T) {
+	trace := []string{"a", "b", "c", "a", "a", "b", "d", "c"}
+	expected := []int{-1, -1, -1, 2, 0, 2, -1, 3}
+	distances := StackDistances(trace)
+	if len(distances) != len(expected) {
+		t.Fatalf("bad distances: %v", distances)
+	}
+	for i, d := range distances {
+		if d != expected[i] {
+			t.Fatalf("bad distances: %v", distances)
+		}
+	}
+
+	if distances = StackDistances[int](nil); len(distances) != 0 {
+		t.Fatalf("bad distances: %v", distances)
+	}
+}
+
+func TestStackDistances_MatchesLRU(t *testing.T) {
+	trace := make([]int, 5000)
+	for i := range trace {
+		trace[i] = rand.Intn(200)
+	}
+	distances := StackDistances(trace)
+
+	// an access hits an LRU of size s iff its distance is below s
+	for _, size := range []int{1, 10, 50, 150} {
+		l, err := simplelru.NewLRU[int, struct{}](size, nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		for i, key := range trace {
+			_, hit := l.Get(key)
+			if expected := distances[i] >= 0 && distances[i] < size; hit != expected {
+				t.Fatalf("size %d access %d: hit %v, distance %d", size, i, hit, distances[i])
+			}
+			if !hit {
+				l.Add(key, struct{}{})
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package lru
+
+// OpKind is the kind of an operation applied by Apply.
+type OpKind int
+
+const (
+	// OpAdd adds the value of the operation, like Add.
+	OpAdd OpKind = iota
+	// OpRemove removes the key of the operation, like Remove.
+	OpRemove
+	// OpTouch looks up the key of the operation, like Get, updating its
+	// "recently used"-ness.
+	OpTouch
+)
+
+// Op is an operation applied by Apply. Value is only used by OpAdd.
+type Op[Key comparable, Value any] struct {
+	Kind  OpKind
+	Key   Key
+	Value Value
+}
+
+// OpResult is the result of an operation applied by Apply.
+type OpResult[Value any] struct {
+	// Present reports whether the key was in the cache before the
+	// operation.
+	Present bool
+	// Value is the value removed by OpRemove or found by OpTouch.
+	Value Value
+	// Evicted reports whether OpAdd evicted an entry.
+	Evicted bool
+}
+
+// Apply applies a batch of operations in order under a single lock, so that
+// other callers observe the cache either before or after the whole batch,
+// e.g. to replay a change set. Each operation sees the effects of the
+// operations before it in the batch, and there is no rollback: all
+// operations are applied. It returns the result of each operation, in the
+// same order. Pending entries reserved with AddPending are reported as
+// missing by OpTouch, which does not wait for them, and operations of an
+// unknown kind are ignored.
+//
+// The eviction callback is invoked once the whole batch is applied, in the
+// order of the evictions.
+func (c *Cache[Key, Value]) Apply(ops []Op[Key, Value]) []OpResult[Value] {
+	results := make([]OpResult[Value], len(ops))
+	c.lock.Lock()
+	for i, op := range ops {
+		r := &results[i]
+		switch op.Kind {
+		case OpAdd:
+			_, r.Present = c.peek(op.Key)
+			r.Evicted = c.add(op.Key, op.Value)
+		case OpRemove:
+			r.Value, r.Present = c.removeEntry(op.Key)
+		case OpTouch:
+			r.Value, r.Present = c.get(op.Key)
+			if _, pending := c.pending[op.Key]; pending {
+				var zeroValue Value
+				r.Value, r.Present = zeroValue, false
+			}
+		}
+	}
+	c.storeLen()
+	c.checkCandidate()
+	ks, vs := c.takeEvicted()
+	c.lock.Unlock()
+	c.notifyEvicted(ks, vs)
+	c.endWarmup()
+	return results
+}
@@ -0,0 +1,56 @@
+package lru
+
+import "testing"
+
+func TestApply(t *testing.T) {
+	var evicted []int
+	l, err := NewWithEvict(2, func(k, v int) {
+		evicted = append(evicted, k)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, 1)
+
+	results := l.Apply([]Op[int, int]{
+		{Kind: OpAdd, Key: 2, Value: 2},
+		{Kind: OpTouch, Key: 1},
+		{Kind: OpAdd, Key: 3, Value: 3},
+		{Kind: OpAdd, Key: 1, Value: 10},
+		{Kind: OpRemove, Key: 3},
+		{Kind: OpRemove, Key: 4},
+		{Kind: OpTouch, Key: 2},
+	})
+	if len(results) != 7 {
+		t.Fatalf("bad results: %v", results)
+	}
+	if r := results[0]; r.Present || r.Evicted {
+		t.Fatalf("bad result: %+v", r)
+	}
+	if r := results[1]; !r.Present || r.Value != 1 {
+		t.Fatalf("bad result: %+v", r)
+	}
+	// the touch of 1 makes 2 the eviction victim
+	if r := results[2]; r.Present || !r.Evicted {
+		t.Fatalf("bad result: %+v", r)
+	}
+	if r := results[3]; !r.Present || r.Evicted {
+		t.Fatalf("bad result: %+v", r)
+	}
+	if r := results[4]; !r.Present || r.Value != 3 {
+		t.Fatalf("bad result: %+v", r)
+	}
+	if r := results[5]; r.Present {
+		t.Fatalf("bad result: %+v", r)
+	}
+	if r := results[6]; r.Present {
+		t.Fatalf("bad result: %+v", r)
+	}
+
+	if len(evicted) != 2 || evicted[0] != 2 || evicted[1] != 3 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	if v, ok := l.Peek(1); !ok || v != 10 || l.Len() != 1 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+}
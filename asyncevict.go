@@ -0,0 +1,122 @@
+package lru
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// WithAsyncEvict runs the eviction callback on a pool of workers goroutines
+// instead of in the goroutine of the operation that evicted the entry, so
+// that a slow callback, e.g. one doing I/O, stays off the hot path. Evicted
+// entries are queued for the workers in a queue holding up to queue
+// entries. When the queue is full, the evicting operation waits for room,
+// unless dropWhenFull is set, in which case the entry is dropped without
+// invoking the callback and counted by DroppedEvictions.
+//
+// With a single worker, the callback is invoked in eviction order; with
+// more, the callbacks run concurrently in no particular order, so the
+// callback must be safe for concurrent use. Close must be called once the
+// cache is no longer used: it waits for the queued entries to be delivered
+// and stops the workers. Entries evicted after Close are delivered
+// synchronously, as without this option. The option requires an eviction
+// callback.
+func WithAsyncEvict[Key comparable, Value any](workers, queue int, dropWhenFull bool) Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		if workers <= 0 {
+			return fmt.Errorf("must provide a positive number of workers")
+		}
+		if queue < 0 {
+			return fmt.Errorf("must provide a non-negative queue size")
+		}
+		if c.onEvictedCB == nil {
+			return fmt.Errorf("must provide an eviction callback")
+		}
+		p := &asyncEvictPool[Key, Value]{
+			onEvicted:    c.onEvictedCB,
+			dropWhenFull: dropWhenFull,
+			queue:        make(chan asyncEvictEntry[Key, Value], queue),
+		}
+		p.wg.Add(workers)
+		for i := 0; i < workers; i++ {
+			go p.work()
+		}
+		c.asyncEvict = p
+		c.onEvictedCB = p.submit
+		return nil
+	}
+}
+
+// asyncEvictEntry is an evicted entry queued for the workers.
+type asyncEvictEntry[Key, Value any] struct {
+	key   Key
+	value Value
+}
+
+// asyncEvictPool delivers evicted entries to the eviction callback from a
+// pool of workers.
+type asyncEvictPool[Key comparable, Value any] struct {
+	// dropped is accessed atomically and kept first for 64-bit alignment.
+	dropped uint64
+
+	onEvicted    func(key Key, value Value)
+	dropWhenFull bool
+	queue        chan asyncEvictEntry[Key, Value]
+	wg           sync.WaitGroup
+
+	// lock guards closed against submissions racing with close.
+	lock   sync.RWMutex
+	closed bool
+}
+
+// submit queues an evicted entry for the workers, or delivers it
+// synchronously once the pool is closed.
+func (p *asyncEvictPool[Key, Value]) submit(key Key, value Value) {
+	p.lock.RLock()
+	if p.closed {
+		p.lock.RUnlock()
+		p.onEvicted(key, value)
+		return
+	}
+	ent := asyncEvictEntry[Key, Value]{key, value}
+	if p.dropWhenFull {
+		select {
+		case p.queue <- ent:
+		default:
+			atomic.AddUint64(&p.dropped, 1)
+		}
+	} else {
+		p.queue <- ent
+	}
+	p.lock.RUnlock()
+}
+
+// work delivers queued entries until the queue is closed.
+func (p *asyncEvictPool[Key, Value]) work() {
+	defer p.wg.Done()
+	for ent := range p.queue {
+		p.onEvicted(ent.key, ent.value)
+	}
+}
+
+// close waits for the queued entries to be delivered and stops the workers.
+func (p *asyncEvictPool[Key, Value]) close() {
+	p.lock.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.lock.Unlock()
+	p.wg.Wait()
+}
+
+// DroppedEvictions returns the number of evicted entries dropped without
+// invoking the eviction callback because the queue of WithAsyncEvict was
+// full. It is always 0 for caches created without the option, or with
+// dropWhenFull unset.
+func (c *Cache[Key, Value]) DroppedEvictions() uint64 {
+	if c.asyncEvict == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&c.asyncEvict.dropped)
+}
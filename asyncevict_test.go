@@ -0,0 +1,99 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestWithAsyncEvict(t *testing.T) {
+	var lock sync.Mutex
+	var evicted []int
+	release := make(chan struct{})
+	onEvicted := func(k, v int) {
+		<-release
+		lock.Lock()
+		evicted = append(evicted, k)
+		lock.Unlock()
+	}
+	l, err := NewWithOptions(2, onEvicted, WithAsyncEvict[int, int](1, 8, false))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// evictions do not wait for the blocked callback
+	for i := 0; i < 6; i++ {
+		l.Add(i, i)
+	}
+	l.Remove(5)
+	close(release)
+	l.Close()
+
+	// a single worker preserves the eviction order
+	lock.Lock()
+	if len(evicted) != 5 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	for i, k := range evicted {
+		if k != i && !(i == 4 && k == 5) {
+			t.Fatalf("bad evicted: %v", evicted)
+		}
+	}
+	lock.Unlock()
+
+	// after Close, evictions are delivered synchronously
+	l.Add(6, 6)
+	l.Add(7, 7)
+	lock.Lock()
+	if len(evicted) != 6 || evicted[5] != 4 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	lock.Unlock()
+	l.Close()
+}
+
+func TestWithAsyncEvict_Drop(t *testing.T) {
+	release := make(chan struct{})
+	delivered := make(chan int, 16)
+	onEvicted := func(k, v int) {
+		<-release
+		delivered <- k
+	}
+	l, err := NewWithOptions(1, onEvicted, WithAsyncEvict[int, int](1, 2, true))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		l.Add(i, i)
+	}
+	// 9 evictions: one held by the worker at most, two queued, the rest
+	// dropped
+	dropped := l.DroppedEvictions()
+	if dropped < 6 || dropped > 7 {
+		t.Fatalf("bad dropped: %v", dropped)
+	}
+	close(release)
+	l.Close()
+	if n := uint64(len(delivered)); n+dropped != 9 {
+		t.Fatalf("bad delivered: %v", n)
+	}
+}
+
+func TestWithAsyncEvict_Invalid(t *testing.T) {
+	onEvicted := func(k, v int) {}
+	if _, err := NewWithOptions[int, int](1, nil, WithAsyncEvict[int, int](1, 1, false)); err == nil {
+		t.Fatalf("should fail without callback")
+	}
+	if _, err := NewWithOptions(1, onEvicted, WithAsyncEvict[int, int](0, 1, false)); err == nil {
+		t.Fatalf("should fail without workers")
+	}
+	if _, err := NewWithOptions(1, onEvicted, WithAsyncEvict[int, int](1, -1, false)); err == nil {
+		t.Fatalf("should fail with negative queue")
+	}
+	l, err := New[int, int](1)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if l.DroppedEvictions() != 0 {
+		t.Fatalf("bad dropped")
+	}
+}
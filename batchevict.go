@@ -0,0 +1,139 @@
+package lru
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/errorhandler/golang-lru/simplelru"
+)
+
+// WithBatchedEvictions delivers evicted entries in batches to flush instead
+// of one at a time, e.g. for an eviction handler that writes to a remote
+// store preferring batched writes. Entries are accumulated and flushed by an
+// internal goroutine as soon as maxBatch entries are pending, or maxDelay
+// after the first pending entry was evicted, whichever comes first. Batches
+// hold at most maxBatch entries, in eviction order, and flush is never
+// called concurrently with itself.
+//
+// Entries are batched for the same events that invoke the eviction callback,
+// which still runs as usual if one is set. Close must be called once the
+// cache is no longer used: it flushes the pending entries and stops the
+// goroutine. Entries evicted after Close are only flushed by another call to
+// Close.
+func WithBatchedEvictions[Key comparable, Value any](maxBatch int, maxDelay time.Duration, flush func([]simplelru.Entry[Key, Value])) Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		if maxBatch <= 0 {
+			return fmt.Errorf("invalid max batch")
+		}
+		if maxDelay <= 0 {
+			return fmt.Errorf("invalid max delay")
+		}
+		if flush == nil {
+			return fmt.Errorf("must provide a flush function")
+		}
+		b := &evictBatcher[Key, Value]{
+			maxBatch: maxBatch,
+			maxDelay: maxDelay,
+			flush:    flush,
+			kick:     make(chan struct{}, 1),
+			closeCh:  make(chan struct{}),
+		}
+		b.wg.Add(1)
+		go b.run()
+		c.evictBatcher = b
+		return nil
+	}
+}
+
+// evictBatcher accumulates evicted entries and flushes them in batches.
+type evictBatcher[Key comparable, Value any] struct {
+	lock     sync.Mutex
+	entries  []simplelru.Entry[Key, Value]
+	maxBatch int
+	maxDelay time.Duration
+
+	// flushLock serializes calls to flush.
+	flushLock sync.Mutex
+	flush     func([]simplelru.Entry[Key, Value])
+
+	kick      chan struct{}
+	closeCh   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// add queues an evicted entry and wakes up the goroutine.
+func (b *evictBatcher[Key, Value]) add(key Key, value Value) {
+	b.lock.Lock()
+	b.entries = append(b.entries, simplelru.Entry[Key, Value]{Key: key, Value: value})
+	b.lock.Unlock()
+	select {
+	case b.kick <- struct{}{}:
+	default:
+	}
+}
+
+// run flushes full batches right away, and partial ones once maxDelay has
+// passed since the goroutine noticed them, until closed.
+func (b *evictBatcher[Key, Value]) run() {
+	defer b.wg.Done()
+	var timer *time.Timer
+	var timerC <-chan time.Time
+	stopTimer := func() {
+		if timer != nil {
+			timer.Stop()
+			timer, timerC = nil, nil
+		}
+	}
+	for {
+		select {
+		case <-b.kick:
+			b.lock.Lock()
+			n := len(b.entries)
+			b.lock.Unlock()
+			if n >= b.maxBatch {
+				stopTimer()
+				b.flushPending()
+			} else if n > 0 && timer == nil {
+				timer = time.NewTimer(b.maxDelay)
+				timerC = timer.C
+			}
+		case <-timerC:
+			timer, timerC = nil, nil
+			b.flushPending()
+		case <-b.closeCh:
+			stopTimer()
+			b.flushPending()
+			return
+		}
+	}
+}
+
+// flushPending flushes all pending entries, in batches of at most maxBatch.
+func (b *evictBatcher[Key, Value]) flushPending() {
+	b.lock.Lock()
+	entries := b.entries
+	b.entries = nil
+	b.lock.Unlock()
+	b.flushLock.Lock()
+	defer b.flushLock.Unlock()
+	for len(entries) > 0 {
+		n := len(entries)
+		if n > b.maxBatch {
+			n = b.maxBatch
+		}
+		b.flush(entries[:n:n])
+		entries = entries[n:]
+	}
+}
+
+// close stops the goroutine after flushing the pending entries, or only
+// flushes them if it is already stopped.
+func (b *evictBatcher[Key, Value]) close() {
+	b.closeOnce.Do(func() {
+		close(b.closeCh)
+	})
+	b.wg.Wait()
+	b.flushPending()
+}
@@ -0,0 +1,92 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/errorhandler/golang-lru/simplelru"
+)
+
+func TestLRUBatchedEvictions(t *testing.T) {
+	var lock sync.Mutex
+	var batches [][]simplelru.Entry[int, int]
+	batchCh := make(chan struct{}, 16)
+	l, err := NewWithOptions(1, nil, WithBatchedEvictions(3, time.Hour, func(entries []simplelru.Entry[int, int]) {
+		lock.Lock()
+		batches = append(batches, entries)
+		lock.Unlock()
+		batchCh <- struct{}{}
+	}))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer l.Close()
+
+	// a full batch is flushed without waiting for the delay
+	for i := 0; i < 4; i++ {
+		l.Add(i, i)
+	}
+	select {
+	case <-batchCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("full batch was not flushed")
+	}
+	lock.Lock()
+	if len(batches) != 1 || len(batches[0]) != 3 || batches[0][0].Key != 0 || batches[0][2].Key != 2 {
+		t.Fatalf("bad batches: %v", batches)
+	}
+	lock.Unlock()
+
+	// pending entries are flushed on close
+	l.Add(4, 4)
+	l.Close()
+	lock.Lock()
+	if len(batches) != 2 || len(batches[1]) != 1 || batches[1][0].Key != 3 {
+		t.Fatalf("bad batches: %v", batches)
+	}
+	lock.Unlock()
+	l.Close()
+}
+
+func TestLRUBatchedEvictions_Delay(t *testing.T) {
+	flushed := make(chan []simplelru.Entry[int, int], 16)
+	evictCounter := 0
+	l, err := NewWithOptions(1, func(k int, v int) {
+		evictCounter++
+	}, WithBatchedEvictions(100, 10*time.Millisecond, func(entries []simplelru.Entry[int, int]) {
+		flushed <- entries
+	}))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer l.Close()
+
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Add(3, 3)
+	select {
+	case entries := <-flushed:
+		if len(entries) != 2 || entries[0].Key != 1 || entries[1].Key != 2 {
+			t.Fatalf("bad entries: %v", entries)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("partial batch was not flushed")
+	}
+	if evictCounter != 2 {
+		t.Fatalf("bad evict count: %v", evictCounter)
+	}
+}
+
+func TestLRUBatchedEvictions_Invalid(t *testing.T) {
+	flush := func([]simplelru.Entry[int, int]) {}
+	if _, err := NewWithOptions(1, nil, WithBatchedEvictions(0, time.Second, flush)); err == nil {
+		t.Fatalf("should fail with invalid max batch")
+	}
+	if _, err := NewWithOptions(1, nil, WithBatchedEvictions(1, 0, flush)); err == nil {
+		t.Fatalf("should fail with invalid max delay")
+	}
+	if _, err := NewWithOptions[int, int](1, nil, WithBatchedEvictions[int, int](1, time.Second, nil)); err == nil {
+		t.Fatalf("should fail without flush function")
+	}
+}
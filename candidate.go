@@ -0,0 +1,40 @@
+package lru
+
+// WithCandidateChangeHook registers a hook that is called whenever the
+// eviction candidate, the entry that the next eviction would remove,
+// changes. This happens when the current candidate is evicted, removed or
+// promoted by Get, when entries are added to an empty cache or one whose
+// promotion is paused, and when the cache is purged or drained. The hook is
+// not called for operations that leave the candidate unchanged.
+//
+// oldCandidate is the zero value if the cache was empty, and newCandidate is
+// the zero value if the cache became empty. The hook runs inside the
+// critical section of the operation that changed the candidate, so it must
+// be fast and must not call methods of the cache, which would deadlock.
+func WithCandidateChangeHook[Key comparable, Value any](hook func(oldCandidate, newCandidate Key)) Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		c.candidateHook = hook
+		return nil
+	}
+}
+
+// checkCandidate calls the candidate change hook if the oldest entry
+// changed since the last check. The caller must hold the lock.
+func (c *Cache[Key, Value]) checkCandidate() {
+	if c.candidateHook == nil {
+		return
+	}
+	k, _, ok := c.lru.GetOldest()
+	if ok == c.hasCandidate && k == c.candidate {
+		return
+	}
+	old := c.candidate
+	c.candidate, c.hasCandidate = k, ok
+	c.candidateHook(old, k)
+}
+
+// EvictionCandidate returns the entry that the next eviction would remove,
+// which is the oldest entry. It is equivalent to GetOldest.
+func (c *Cache[Key, Value]) EvictionCandidate() (key Key, value Value, ok bool) {
+	return c.GetOldest()
+}
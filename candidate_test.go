@@ -0,0 +1,51 @@
+package lru
+
+import "testing"
+
+func TestLRUCandidateChangeHook(t *testing.T) {
+	type change struct{ old, new int }
+	var changes []change
+	l, err := NewWithOptions(3, nil, WithCandidateChangeHook[int, int](func(oldCandidate, newCandidate int) {
+		changes = append(changes, change{oldCandidate, newCandidate})
+	}))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	expect := func(expected ...change) {
+		t.Helper()
+		if len(changes) != len(expected) {
+			t.Fatalf("bad changes: %v", changes)
+		}
+		for i := range expected {
+			if changes[i] != expected[i] {
+				t.Fatalf("bad changes: %v", changes)
+			}
+		}
+		changes = nil
+	}
+
+	l.Add(1, 1)
+	expect(change{0, 1})
+	l.Add(2, 2)
+	l.Add(3, 3)
+	l.Get(3)
+	expect()
+
+	l.Get(1)
+	expect(change{1, 2})
+	l.Add(4, 4) // evicts 2
+	expect(change{2, 3})
+	l.Remove(1)
+	expect()
+	l.Remove(3)
+	expect(change{3, 4})
+
+	if k, v, ok := l.EvictionCandidate(); !ok || k != 4 || v != 4 {
+		t.Fatalf("bad candidate: %v %v %v", k, v, ok)
+	}
+
+	l.Purge()
+	expect(change{4, 0})
+	l.Purge()
+	expect()
+}
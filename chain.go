@@ -0,0 +1,172 @@
+package lru
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/errorhandler/golang-lru/simplelru"
+)
+
+// Chain is a thread-safe multi-level cache composed of an ordered list of
+// caches, from the smallest and fastest (level 0) to the largest and slowest.
+//
+// Get walks the levels in order and stops at the first hit. A hit at level i
+// is back-filled into levels 0..i-1, so that the next lookup of the same key
+// is served by level 0.
+//
+// How Add writes to the levels depends on the WritePolicy of the chain.
+// Remove and Purge always apply to every level.
+type Chain[Key, Value any] struct {
+	levels []simplelru.LRUCache[Key, Value]
+	policy WritePolicy
+	lock   sync.RWMutex
+}
+
+// WritePolicy selects how a Chain writes added entries to its levels.
+type WritePolicy int
+
+const (
+	// WriteFirstLevel adds entries to level 0 only, and relies on back-fill
+	// to move entries between levels. Entries evicted from a level are
+	// dropped.
+	WriteFirstLevel WritePolicy = iota
+
+	// WriteThrough adds entries to every level, so that all levels hold
+	// every entry that fits in them.
+	WriteThrough
+
+	// WriteBack adds entries to level 0 only, and moves an entry evicted
+	// from a level to the next one, by Add or by back-fill, so that entries
+	// only leave the chain once evicted from the last level. Until then,
+	// the lower levels lag behind: an entry can be in level 0 but not yet
+	// in level 1, and a value updated in level 0 is stale in the lower
+	// levels until the entry is evicted to them. Propagation relies on the
+	// levels evicting their oldest entry, as reported by GetOldest, when
+	// Add reports an eviction, as the caches of simplelru do.
+	WriteBack
+)
+
+// NewChain creates a Chain over the given levels that adds new entries to
+// level 0 only.
+func NewChain[Key, Value any](levels ...simplelru.LRUCache[Key, Value]) (*Chain[Key, Value], error) {
+	return NewChainWithWritePolicy(WriteFirstLevel, levels...)
+}
+
+// NewChainWriteThrough creates a Chain over the given levels that adds new
+// entries to all levels.
+func NewChainWriteThrough[Key, Value any](levels ...simplelru.LRUCache[Key, Value]) (*Chain[Key, Value], error) {
+	return NewChainWithWritePolicy(WriteThrough, levels...)
+}
+
+// NewChainWithWritePolicy creates a Chain over the given levels that adds
+// new entries according to the write policy.
+func NewChainWithWritePolicy[Key, Value any](policy WritePolicy, levels ...simplelru.LRUCache[Key, Value]) (*Chain[Key, Value], error) {
+	if policy < WriteFirstLevel || policy > WriteBack {
+		return nil, fmt.Errorf("invalid write policy")
+	}
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("must provide at least one level")
+	}
+	for _, level := range levels {
+		if level == nil {
+			return nil, fmt.Errorf("invalid level")
+		}
+	}
+	c := &Chain[Key, Value]{
+		levels: append([]simplelru.LRUCache[Key, Value](nil), levels...),
+		policy: policy,
+	}
+	return c, nil
+}
+
+// Get looks up a key's value from the chain, back-filling it into all
+// levels in front of the one it was found in.
+func (c *Chain[Key, Value]) Get(key Key) (value Value, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for i, level := range c.levels {
+		if value, ok = level.Get(key); ok {
+			for j := 0; j < i; j++ {
+				c.addToLevel(j, key, value)
+			}
+			return value, true
+		}
+	}
+	return
+}
+
+// Peek returns the key value from the first level that contains it, without
+// back-filling or updating the "recently used"-ness of the key.
+func (c *Chain[Key, Value]) Peek(key Key) (value Value, ok bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for _, level := range c.levels {
+		if value, ok = level.Peek(key); ok {
+			return value, true
+		}
+	}
+	return
+}
+
+// Contains checks if any level contains the key, without updating the
+// recent-ness or back-filling it.
+func (c *Chain[Key, Value]) Contains(key Key) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for _, level := range c.levels {
+		if level.Contains(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add adds a value to level 0, or to every level if the chain writes
+// through.
+func (c *Chain[Key, Value]) Add(key Key, value Value) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.policy != WriteThrough {
+		c.addToLevel(0, key, value)
+		return
+	}
+	for _, level := range c.levels {
+		level.Add(key, value)
+	}
+}
+
+// addToLevel adds a value to level i, moving the entry it evicts to the next
+// level if the chain writes back.
+func (c *Chain[Key, Value]) addToLevel(i int, key Key, value Value) {
+	level := c.levels[i]
+	if c.policy != WriteBack || i == len(c.levels)-1 {
+		level.Add(key, value)
+		return
+	}
+	oldestKey, oldestValue, ok := level.GetOldest()
+	if level.Add(key, value) && ok {
+		c.addToLevel(i+1, oldestKey, oldestValue)
+	}
+}
+
+// Remove removes the provided key from all levels, returning if the key
+// was contained in any of them.
+func (c *Chain[Key, Value]) Remove(key Key) (present bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, level := range c.levels {
+		if level.Remove(key) {
+			present = true
+		}
+	}
+	return
+}
+
+// Purge is used to completely clear all levels.
+func (c *Chain[Key, Value]) Purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, level := range c.levels {
+		level.Purge()
+	}
+}
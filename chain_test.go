@@ -0,0 +1,178 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/errorhandler/golang-lru/simplelru"
+)
+
+func newTestChainLevels(t *testing.T, sizes ...int) []simplelru.LRUCache[int, int] {
+	levels := make([]simplelru.LRUCache[int, int], 0, len(sizes))
+	for _, size := range sizes {
+		l, err := simplelru.NewLRU[int, int](size, nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		levels = append(levels, l)
+	}
+	return levels
+}
+
+func TestChain_Invalid(t *testing.T) {
+	if _, err := NewChain[int, int](); err == nil {
+		t.Fatalf("should fail without levels")
+	}
+	if _, err := NewChain[int, int](nil); err == nil {
+		t.Fatalf("should fail with nil level")
+	}
+}
+
+func TestChain_GetBackfill(t *testing.T) {
+	levels := newTestChainLevels(t, 2, 4, 8)
+	c, err := NewChain(levels...)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	levels[2].Add(1, 1)
+	if v, ok := c.Get(1); !ok || v != 1 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	for i, level := range levels {
+		if !level.Contains(1) {
+			t.Fatalf("level %d should contain 1", i)
+		}
+	}
+
+	levels[1].Add(2, 2)
+	if v, ok := c.Get(2); !ok || v != 2 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	if !levels[0].Contains(2) {
+		t.Fatalf("level 0 should contain 2")
+	}
+	if levels[2].Contains(2) {
+		t.Fatalf("level 2 should not contain 2")
+	}
+
+	if _, ok := c.Get(3); ok {
+		t.Fatalf("should miss")
+	}
+}
+
+func TestChain_Peek(t *testing.T) {
+	levels := newTestChainLevels(t, 2, 4)
+	c, err := NewChain(levels...)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	levels[1].Add(1, 1)
+	if v, ok := c.Peek(1); !ok || v != 1 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	if !c.Contains(1) {
+		t.Fatalf("should contain 1")
+	}
+	if levels[0].Contains(1) {
+		t.Fatalf("peek should not back-fill")
+	}
+}
+
+func TestChain_Add(t *testing.T) {
+	levels := newTestChainLevels(t, 2, 4)
+	c, err := NewChain(levels...)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	c.Add(1, 1)
+	if !levels[0].Contains(1) || levels[1].Contains(1) {
+		t.Fatalf("should only add to level 0")
+	}
+
+	levels = newTestChainLevels(t, 2, 4)
+	c, err = NewChainWriteThrough(levels...)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	c.Add(1, 1)
+	if !levels[0].Contains(1) || !levels[1].Contains(1) {
+		t.Fatalf("should add to all levels")
+	}
+}
+
+func TestChain_Remove(t *testing.T) {
+	levels := newTestChainLevels(t, 2, 4)
+	c, err := NewChainWriteThrough(levels...)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	c.Add(1, 1)
+	c.Add(2, 2)
+	if !c.Remove(1) {
+		t.Fatalf("should be contained")
+	}
+	if c.Remove(1) {
+		t.Fatalf("should not be contained")
+	}
+	if levels[0].Contains(1) || levels[1].Contains(1) {
+		t.Fatalf("should be removed from all levels")
+	}
+
+	c.Purge()
+	if levels[0].Len() != 0 || levels[1].Len() != 0 {
+		t.Fatalf("should purge all levels")
+	}
+}
+
+func TestChain_WriteBack(t *testing.T) {
+	levels := newTestChainLevels(t, 2, 2, 2)
+	c, err := NewChainWithWritePolicy(WriteBack, levels...)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	c.Add(1, 1)
+	c.Add(2, 2)
+	if levels[1].Len() != 0 {
+		t.Fatalf("should only add to level 0")
+	}
+
+	// evictions from level 0 move to level 1, then to level 2
+	c.Add(3, 3)
+	if !levels[1].Contains(1) || levels[0].Contains(1) {
+		t.Fatalf("1 should be written back to level 1")
+	}
+	c.Add(4, 4)
+	c.Add(5, 5)
+	c.Add(6, 6)
+	if !levels[2].Contains(1) || !levels[2].Contains(2) {
+		t.Fatalf("1 and 2 should be written back to level 2")
+	}
+	for k := 1; k <= 6; k++ {
+		if !c.Contains(k) {
+			t.Fatalf("chain should contain %d", k)
+		}
+	}
+
+	// only evictions from the last level leave the chain
+	c.Add(7, 7)
+	if c.Contains(1) {
+		t.Fatalf("1 should leave the chain")
+	}
+
+	// back-fill evictions are written back too
+	if v, ok := c.Get(2); !ok || v != 2 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	if !levels[0].Contains(2) || !levels[1].Contains(6) {
+		t.Fatalf("6 should be written back to level 1")
+	}
+}
+
+func TestChain_InvalidWritePolicy(t *testing.T) {
+	levels := newTestChainLevels(t, 2)
+	if _, err := NewChainWithWritePolicy(WritePolicy(-1), levels...); err == nil {
+		t.Fatalf("should fail with invalid write policy")
+	}
+}
@@ -0,0 +1,19 @@
+package lru
+
+import (
+	"fmt"
+	"time"
+)
+
+// WithClock sets the clock used by the time-based options of the cache,
+// such as WithEvictionRateWindow, WithWriteThrottle and WithTimestamps, in
+// place of time.Now, e.g. to control time in tests.
+func WithClock[Key comparable, Value any](now func() time.Time) Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		if now == nil {
+			return fmt.Errorf("must provide a clock")
+		}
+		c.now = now
+		return nil
+	}
+}
@@ -0,0 +1,30 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithClock(t *testing.T) {
+	now := time.Unix(1000, 0)
+	clock := func() time.Time { return now }
+	l, err := NewWithOptions(1, nil, WithClock[int, int](clock))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !l.now().Equal(now) {
+		t.Fatalf("bad clock")
+	}
+
+	l, err = New[int, int](1)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if l.now == nil {
+		t.Fatalf("should default to time.Now")
+	}
+
+	if _, err := NewWithOptions[int, int](1, nil, WithClock[int, int](nil)); err == nil {
+		t.Fatalf("should fail without clock")
+	}
+}
@@ -0,0 +1,69 @@
+package lru
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+)
+
+// WithCollisionDetection helps to catch composite keys that compare equal
+// by accident during development, e.g. because a field that identifies the
+// entry was left out of the key, or a pointer field makes two keys built
+// from the same data compare unequal elsewhere. checksum must summarize the
+// fields of a key that the caller considers its identity. When a value is
+// added for a key that is already in the cache, the checksum of the new key
+// is compared with the one of the key it was stored with, and a mismatch,
+// meaning that the two keys are equal for the cache but different for the
+// caller, is logged and counted by CollisionCount.
+//
+// The checksums cost a map entry per cached key and a call to checksum on
+// every add, so the option is meant for tests and development rather than
+// production. checksum runs inside the critical section of the adding
+// operations, so it must not call methods of the cache.
+func WithCollisionDetection[Key comparable, Value any](checksum func(Key) uint64) Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		if checksum == nil {
+			return fmt.Errorf("must provide a checksum function")
+		}
+		c.collisions = &collisionDetector[Key]{
+			checksum: checksum,
+			sums:     make(map[Key]uint64),
+		}
+		return nil
+	}
+}
+
+// collisionDetector holds the checksum of each key in the cache.
+type collisionDetector[Key comparable] struct {
+	// count is accessed atomically and kept first for 64-bit alignment.
+	count uint64
+
+	checksum func(Key) uint64
+	sums     map[Key]uint64
+}
+
+// check compares the checksum of a key being added with the one it was
+// stored with, then records the new one.
+func (d *collisionDetector[Key]) check(key Key) {
+	sum := d.checksum(key)
+	if old, ok := d.sums[key]; ok && old != sum {
+		atomic.AddUint64(&d.count, 1)
+		log.Printf("[WARN] lru: key %v collides with an existing key (checksum %x != %x)", key, sum, old)
+	}
+	d.sums[key] = sum
+}
+
+// evict drops the checksum of a key that left the cache.
+func (d *collisionDetector[Key]) evict(key Key) {
+	delete(d.sums, key)
+}
+
+// CollisionCount returns the number of collisions detected by
+// WithCollisionDetection. It is always 0 for caches created without the
+// option.
+func (c *Cache[Key, Value]) CollisionCount() int {
+	if c.collisions == nil {
+		return 0
+	}
+	return int(atomic.LoadUint64(&c.collisions.count))
+}
@@ -0,0 +1,59 @@
+package lru
+
+import "testing"
+
+type collisionMeta struct {
+	version uint64
+}
+
+type collisionKey struct {
+	id   int
+	meta *collisionMeta
+}
+
+func TestWithCollisionDetection(t *testing.T) {
+	checksum := func(k collisionKey) uint64 {
+		return uint64(k.id)<<32 | k.meta.version
+	}
+	l, err := NewWithOptions[collisionKey, int](2, nil, WithCollisionDetection[collisionKey, int](checksum))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	meta := &collisionMeta{version: 1}
+	l.Add(collisionKey{1, meta}, 1)
+	l.Add(collisionKey{1, meta}, 2)
+	l.Add(collisionKey{2, &collisionMeta{}}, 2)
+	if l.CollisionCount() != 0 {
+		t.Fatalf("bad collision count: %v", l.CollisionCount())
+	}
+
+	// the key compares equal, but no longer identifies the same data
+	meta.version = 2
+	l.Add(collisionKey{1, meta}, 3)
+	if l.CollisionCount() != 1 {
+		t.Fatalf("bad collision count: %v", l.CollisionCount())
+	}
+
+	// evicted keys are forgotten
+	l.Add(collisionKey{3, &collisionMeta{}}, 3)
+	l.Add(collisionKey{4, &collisionMeta{}}, 4)
+	meta.version = 3
+	l.Add(collisionKey{1, meta}, 4)
+	if l.CollisionCount() != 1 || len(l.collisions.sums) != 2 {
+		t.Fatalf("bad collision count: %v", l.CollisionCount())
+	}
+}
+
+func TestWithCollisionDetection_Invalid(t *testing.T) {
+	if _, err := NewWithOptions[int, int](2, nil, WithCollisionDetection[int, int](nil)); err == nil {
+		t.Fatalf("should fail without checksum")
+	}
+	l, err := New[int, int](2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if l.CollisionCount() != 0 {
+		t.Fatalf("bad collision count")
+	}
+}
@@ -1,4 +1,4 @@
-// Package lru provides three different LRU caches of varying sophistication.
+// Package lru provides several LRU caches of varying sophistication.
 //
 // Cache is a simple LRU cache. It is based on the
 // LRU implementation in groupcache:
@@ -16,6 +16,18 @@
 // ARC has been patented by IBM, so do not use it if that is problematic for
 // your program.
 //
-// All caches in this package take locks while operating, and are therefore
-// thread-safe for consumers.
+// Chain composes several caches into a multi-level cache, reading from the
+// fastest level first and back-filling hits into the levels in front of it.
+//
+// SyncMapLRU exposes a Cache through the methods of sync.Map, to bound the
+// size of maps migrated from sync.Map.
+//
+// GenerationalLRU ages entries out a generation at a time, for caches
+// bucketed by time interval.
+//
+// SeqlockLRU serves reads without taking a lock, for small read-mostly
+// caches, at the cost of approximate recency and costlier writes.
+//
+// All caches in this package are thread-safe for consumers. All of them take
+// locks while operating, except for the reads of SeqlockLRU.
 package lru
@@ -0,0 +1,82 @@
+package lru
+
+import (
+	"fmt"
+	"time"
+)
+
+// evictionRateBuckets is the number of buckets the eviction rate window is
+// divided into.
+const evictionRateBuckets = 10
+
+// WithEvictionRateWindow makes the cache track the rate at which Adds evict
+// entries over a sliding window of the given duration, as reported by
+// EvictionRate. A high sustained rate means the cache is too small for its
+// working set and is thrashing. Evictions by Remove, Resize or Purge are not
+// counted.
+//
+// The window is divided into ten buckets, each counting the evictions of a
+// tenth of the window. Buckets expire as a whole, so the reported rate
+// covers between nine tenths of the window and the full window, and may be
+// off by up to a bucket's worth of evictions near its boundaries. Time is
+// read from the clock set with WithClock.
+func WithEvictionRateWindow[Key comparable, Value any](d time.Duration) Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		if d < evictionRateBuckets {
+			return fmt.Errorf("invalid eviction rate window")
+		}
+		c.evictionRate = &evictionRate{
+			window: d,
+			width:  d / evictionRateBuckets,
+		}
+		return nil
+	}
+}
+
+// evictionRate is a ring of eviction counts over consecutive time buckets.
+// It is protected by the cache lock.
+type evictionRate struct {
+	window time.Duration
+	width  time.Duration
+	starts [evictionRateBuckets]int64
+	counts [evictionRateBuckets]uint64
+	now    func() time.Time
+}
+
+// record counts an eviction in the current bucket, recycling the bucket if
+// it last counted an older period.
+func (r *evictionRate) record() {
+	n := r.now().UnixNano()
+	start := n - n%int64(r.width)
+	i := (start / int64(r.width)) % evictionRateBuckets
+	if r.starts[i] != start {
+		r.starts[i] = start
+		r.counts[i] = 0
+	}
+	r.counts[i]++
+}
+
+// rate returns the evictions per second over the buckets within the window.
+func (r *evictionRate) rate() float64 {
+	n := r.now().UnixNano()
+	oldest := n - n%int64(r.width) - int64(r.window) + int64(r.width)
+	var total uint64
+	for i, start := range r.starts {
+		if start >= oldest && start <= n {
+			total += r.counts[i]
+		}
+	}
+	return float64(total) / r.window.Seconds()
+}
+
+// EvictionRate returns the number of evictions per second caused by Adds
+// over the window configured with WithEvictionRateWindow, or 0 if the cache
+// was not created with that option.
+func (c *Cache[Key, Value]) EvictionRate() float64 {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.evictionRate == nil {
+		return 0
+	}
+	return c.evictionRate.rate()
+}
@@ -0,0 +1,64 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUEvictionRate(t *testing.T) {
+	now := time.Unix(1000, 0)
+	clock := func() time.Time { return now }
+	l, err := NewWithOptions(1, nil,
+		WithEvictionRateWindow[int, int](10*time.Second),
+		WithClock[int, int](clock))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if r := l.EvictionRate(); r != 0 {
+		t.Fatalf("bad rate: %v", r)
+	}
+
+	// 20 evictions within the first second
+	for i := 0; i <= 20; i++ {
+		l.Add(i, i)
+	}
+	l.Remove(20)
+	if r := l.EvictionRate(); r != 2 {
+		t.Fatalf("bad rate: %v", r)
+	}
+
+	// 10 more evictions 5 seconds later
+	now = now.Add(5 * time.Second)
+	for i := 0; i < 10; i++ {
+		l.Add(i, i)
+	}
+	if r := l.EvictionRate(); r != 2.9 {
+		t.Fatalf("bad rate: %v", r)
+	}
+
+	// the first bucket slides out of the window
+	now = now.Add(5 * time.Second)
+	if r := l.EvictionRate(); r != 0.9 {
+		t.Fatalf("bad rate: %v", r)
+	}
+	now = now.Add(5 * time.Second)
+	if r := l.EvictionRate(); r != 0 {
+		t.Fatalf("bad rate: %v", r)
+	}
+}
+
+func TestLRUEvictionRate_Invalid(t *testing.T) {
+	if _, err := NewWithOptions(1, nil, WithEvictionRateWindow[int, int](0)); err == nil {
+		t.Fatalf("should fail with invalid window")
+	}
+	l, err := New[int, int](1)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, 1)
+	l.Add(2, 2)
+	if r := l.EvictionRate(); r != 0 {
+		t.Fatalf("bad rate: %v", r)
+	}
+}
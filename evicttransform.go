@@ -0,0 +1,25 @@
+package lru
+
+import "fmt"
+
+// WithEvictTransform sets a transform that is applied to the value of each
+// entry leaving the cache, before it is passed to the eviction callback and
+// to the flush of WithBatchedEvictions, e.g. to compress or strip the value
+// before it is spilled elsewhere. The transform runs for the same events
+// that invoke the eviction callback, in the eviction path, before the
+// callback, and only if a callback or WithBatchedEvictions is set. The
+// value is being dropped from the cache, so the transform may modify it in
+// place.
+//
+// The transform runs inside the critical section of the operation that
+// evicted the entry, so it must be fast and must not call methods of the
+// cache, which would deadlock.
+func WithEvictTransform[Key comparable, Value any](transform func(Key, Value) Value) Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		if transform == nil {
+			return fmt.Errorf("must provide a transform")
+		}
+		c.evictTransform = transform
+		return nil
+	}
+}
@@ -0,0 +1,35 @@
+package lru
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithEvictTransform(t *testing.T) {
+	var evicted []string
+	l, err := NewWithOptions(2, func(k int, v string) {
+		evicted = append(evicted, v)
+	}, WithEvictTransform(func(k int, v string) string {
+		return strings.ToUpper(v)
+	}))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add(1, "a")
+	l.Add(2, "b")
+	l.Add(3, "c")
+	l.Remove(2)
+	if len(evicted) != 2 || evicted[0] != "A" || evicted[1] != "B" {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+
+	// stored values are not transformed
+	if v, _ := l.Peek(3); v != "c" {
+		t.Fatalf("bad value: %v", v)
+	}
+
+	if _, err := NewWithOptions[int, string](2, nil, WithEvictTransform[int, string](nil)); err == nil {
+		t.Fatalf("should fail without transform")
+	}
+}
@@ -0,0 +1,108 @@
+package lru
+
+import "sort"
+
+// WithGenerations makes the cache stamp each entry with a generation, a
+// number taken from a counter that is bumped each time a value is added,
+// so that ReadHandle and HasChangedSince can detect when the value of a key
+// is replaced without comparing values, and ChangedSince can list the keys
+// changed since a generation. This costs a map entry per cached key.
+func WithGenerations[Key comparable, Value any]() Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		c.generations = &generations[Key]{
+			gens: make(map[Key]uint64),
+		}
+		return nil
+	}
+}
+
+// generations holds the generation of each entry, and the counter they are
+// taken from.
+type generations[Key comparable] struct {
+	clock uint64
+	gens  map[Key]uint64
+}
+
+// stamp gives the key a new generation.
+func (g *generations[Key]) stamp(key Key) {
+	g.clock++
+	g.gens[key] = g.clock
+}
+
+// evict drops the generation of a key that left the cache.
+func (g *generations[Key]) evict(key Key) {
+	delete(g.gens, key)
+}
+
+// ReadHandle looks up a key's value like Get, along with a token that
+// identifies the current value of the key for HasChangedSince. Unlike Get,
+// it does not wait for a pending entry reserved with AddPending, and
+// reports it as missing instead.
+//
+// The cache must be created with WithGenerations, otherwise the token is
+// always 0.
+func (c *Cache[Key, Value]) ReadHandle(key Key) (value Value, token uint64, ok bool) {
+	c.lock.Lock()
+	value, ok = c.get(key)
+	if _, pending := c.pending[key]; pending {
+		var zeroValue Value
+		value, ok = zeroValue, false
+	}
+	if ok && c.generations != nil {
+		token = c.generations.gens[key]
+	}
+	ks, vs := c.takeEvicted()
+	c.lock.Unlock()
+	c.notifyEvicted(ks, vs)
+	return value, token, ok
+}
+
+// HasChangedSince reports whether the value of the key was replaced since
+// ReadHandle returned the token, and whether the key is in the cache. A key
+// that was removed or evicted and then added again counts as changed, even
+// if its value is the same. A key that is not in the cache is reported as
+// changed.
+//
+// The cache must be created with WithGenerations, otherwise every key is
+// reported as changed.
+func (c *Cache[Key, Value]) HasChangedSince(key Key, token uint64) (changed, present bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if _, pending := c.pending[key]; pending || !c.lru.Contains(key) {
+		return true, false
+	}
+	if c.generations == nil {
+		return true, true
+	}
+	return c.generations.gens[key] != token, true
+}
+
+// ChangedSince returns the keys whose value was added or replaced after
+// the cache generation gen, in the order of their latest change, along with
+// the current generation of the cache, to pass as gen to the next call. It
+// supports polling the cache for changes, starting from generation 0,
+// without missing any. A key that was removed or evicted and then added
+// again is reported as changed. Keys that left the cache, whether removed,
+// evicted or purged, are not reported; use the eviction callback to track
+// them. Pending entries reserved with AddPending are reported once filled.
+// It walks every entry, so it is O(n log n).
+//
+// The cache must be created with WithGenerations, otherwise no keys are
+// reported and the generation is always 0.
+func (c *Cache[Key, Value]) ChangedSince(gen uint64) (keys []Key, current uint64) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.generations == nil {
+		return nil, 0
+	}
+	for key, g := range c.generations.gens {
+		if _, pending := c.pending[key]; g > gen && !pending {
+			keys = append(keys, key)
+		}
+	}
+	gens := c.generations.gens
+	sort.Slice(keys, func(i, j int) bool {
+		return gens[keys[i]] < gens[keys[j]]
+	})
+	return keys, c.generations.clock
+}
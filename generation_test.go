@@ -0,0 +1,117 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithGenerations(t *testing.T) {
+	l, err := NewWithOptions[int, int](2, nil, WithGenerations[int, int]())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add(1, 1)
+	v, token, ok := l.ReadHandle(1)
+	if !ok || v != 1 || token == 0 {
+		t.Fatalf("bad: %v %v %v", v, token, ok)
+	}
+	if changed, present := l.HasChangedSince(1, token); changed || !present {
+		t.Fatalf("bad: %v %v", changed, present)
+	}
+
+	// adding other keys does not change 1
+	l.Add(2, 2)
+	l.Get(1)
+	if changed, _ := l.HasChangedSince(1, token); changed {
+		t.Fatalf("should not be changed")
+	}
+
+	// replacing the value changes it, even with an equal value
+	l.Add(1, 1)
+	if changed, present := l.HasChangedSince(1, token); !changed || !present {
+		t.Fatalf("bad: %v %v", changed, present)
+	}
+	_, token, _ = l.ReadHandle(1)
+
+	// remove and re-add counts as a change
+	l.Remove(1)
+	if changed, present := l.HasChangedSince(1, token); !changed || present {
+		t.Fatalf("bad: %v %v", changed, present)
+	}
+	l.Add(1, 1)
+	if changed, present := l.HasChangedSince(1, token); !changed || !present {
+		t.Fatalf("bad: %v %v", changed, present)
+	}
+
+	// pending entries are missing until filled
+	fill, _ := l.AddPending(3)
+	if _, _, ok := l.ReadHandle(3); ok {
+		t.Fatalf("pending entry should miss")
+	}
+	fill(3)
+	if v, token, ok := l.ReadHandle(3); !ok || v != 3 || token == 0 {
+		t.Fatalf("bad: %v %v %v", v, token, ok)
+	}
+}
+
+func TestWithGenerations_Disabled(t *testing.T) {
+	l, err := New[int, int](2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, 1)
+	v, token, ok := l.ReadHandle(1)
+	if !ok || v != 1 || token != 0 {
+		t.Fatalf("bad: %v %v %v", v, token, ok)
+	}
+	if changed, present := l.HasChangedSince(1, token); !changed || !present {
+		t.Fatalf("bad: %v %v", changed, present)
+	}
+}
+
+func TestChangedSince(t *testing.T) {
+	l, err := NewWithOptions(4, nil, WithGenerations[int, int]())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Add(1, 10)
+	keys, gen := l.ChangedSince(0)
+	if !reflect.DeepEqual(keys, []int{2, 1}) {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	if keys, _ := l.ChangedSince(gen); len(keys) != 0 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+
+	// reads do not count as changes, while updates and re-adds do
+	l.Get(2)
+	l.UpdateAll(func(k, v int) (int, bool) { return v, k != 1 })
+	l.Add(1, 1)
+	l.Add(3, 3)
+	fill, _ := l.AddPending(4)
+	keys, gen = l.ChangedSince(gen)
+	if !reflect.DeepEqual(keys, []int{2, 1, 3}) {
+		t.Fatalf("bad keys: %v", keys)
+	}
+
+	// pending entries are reported once filled
+	fill(4)
+	if keys, _ = l.ChangedSince(gen); !reflect.DeepEqual(keys, []int{4}) {
+		t.Fatalf("bad keys: %v", keys)
+	}
+}
+
+func TestChangedSince_Disabled(t *testing.T) {
+	l, err := New[int, int](2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, 1)
+	if keys, gen := l.ChangedSince(0); keys != nil || gen != 0 {
+		t.Fatalf("bad: %v %v", keys, gen)
+	}
+}
@@ -0,0 +1,190 @@
+package lru
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/errorhandler/golang-lru/simplelru"
+)
+
+// GenerationalLRU is a thread-safe cache that ages entries out by
+// generation rather than one by one, e.g. to keep the data of the current
+// and previous time intervals by calling Rotate at the start of each
+// interval. Entries are added to the current generation, an LRU of fixed
+// size. Rotate makes the current generation the previous one, starts an
+// empty current generation, and evicts every entry of the oldest one.
+//
+// Get looks a key up in the current generation first, then in the older
+// ones, from the newest to the oldest, and moves a hit from an older
+// generation into the current one, so that entries in use survive
+// rotations. A miss costs one map lookup per generation. An entry moved
+// between generations, by Get or by Add of a key held by an older
+// generation, is not evicted, while an entry evicted to make room in the
+// current generation is.
+type GenerationalLRU[Key comparable, Value any] struct {
+	// gens holds the generations, from the current one to the oldest.
+	gens        []*simplelru.LRU[Key, Value]
+	sizePerGen  int
+	onEvictedCB func(k Key, v Value)
+	lock        sync.RWMutex
+}
+
+// NewGenerationalLRU creates a GenerationalLRU keeping the given number of
+// generations of up to sizePerGen entries each, with an optional eviction
+// callback.
+func NewGenerationalLRU[Key comparable, Value any](sizePerGen, generations int, onEvict simplelru.EvictCallback[Key, Value]) (*GenerationalLRU[Key, Value], error) {
+	if sizePerGen <= 0 {
+		return nil, fmt.Errorf("invalid size per generation")
+	}
+	if generations <= 0 {
+		return nil, fmt.Errorf("invalid number of generations")
+	}
+	c := &GenerationalLRU[Key, Value]{
+		gens:        make([]*simplelru.LRU[Key, Value], generations),
+		sizePerGen:  sizePerGen,
+		onEvictedCB: onEvict,
+	}
+	for i := range c.gens {
+		gen, err := simplelru.NewLRU[Key, Value](sizePerGen, nil)
+		if err != nil {
+			return nil, err
+		}
+		c.gens[i] = gen
+	}
+	return c, nil
+}
+
+// Add adds a value to the current generation, moving the key out of an
+// older generation that holds it. Returns true if an eviction occurred.
+func (c *GenerationalLRU[Key, Value]) Add(key Key, value Value) (evicted bool) {
+	c.lock.Lock()
+	k, v, evicted := c.add(key, value)
+	c.lock.Unlock()
+	if evicted && c.onEvictedCB != nil {
+		c.onEvictedCB(k, v)
+	}
+	return evicted
+}
+
+// add implements Add, returning the evicted entry. The caller must hold the
+// lock.
+func (c *GenerationalLRU[Key, Value]) add(key Key, value Value) (k Key, v Value, evicted bool) {
+	for _, gen := range c.gens[1:] {
+		gen.Remove(key)
+	}
+	current := c.gens[0]
+	k, v, ok := current.GetOldest()
+	evicted = current.Add(key, value) && ok
+	return k, v, evicted
+}
+
+// Get looks up a key's value, moving it into the current generation if it
+// is found in an older one.
+func (c *GenerationalLRU[Key, Value]) Get(key Key) (value Value, ok bool) {
+	var k Key
+	var v Value
+	var evicted bool
+	c.lock.Lock()
+	if value, ok = c.gens[0].Get(key); !ok {
+		for _, gen := range c.gens[1:] {
+			if value, ok = gen.Peek(key); ok {
+				k, v, evicted = c.add(key, value)
+				break
+			}
+		}
+	}
+	c.lock.Unlock()
+	if evicted && c.onEvictedCB != nil {
+		c.onEvictedCB(k, v)
+	}
+	return value, ok
+}
+
+// Peek returns the key value (or undefined if not found) without moving it
+// between generations or updating its recent-ness.
+func (c *GenerationalLRU[Key, Value]) Peek(key Key) (value Value, ok bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for _, gen := range c.gens {
+		if value, ok = gen.Peek(key); ok {
+			return value, true
+		}
+	}
+	return
+}
+
+// Contains checks if a key is in any generation, without moving it between
+// generations or updating its recent-ness.
+func (c *GenerationalLRU[Key, Value]) Contains(key Key) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for _, gen := range c.gens {
+		if gen.Contains(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Remove removes the provided key from the cache, returning if the key was
+// contained.
+func (c *GenerationalLRU[Key, Value]) Remove(key Key) (present bool) {
+	var value Value
+	c.lock.Lock()
+	for _, gen := range c.gens {
+		if value, present = gen.Peek(key); present {
+			gen.Remove(key)
+			break
+		}
+	}
+	c.lock.Unlock()
+	if present && c.onEvictedCB != nil {
+		c.onEvictedCB(key, value)
+	}
+	return present
+}
+
+// Rotate starts a new, empty, current generation, and evicts the entries of
+// the oldest generation, from its oldest to its newest. Returns the number
+// of entries evicted.
+func (c *GenerationalLRU[Key, Value]) Rotate() (evicted int) {
+	c.lock.Lock()
+	last := len(c.gens) - 1
+	oldest := c.gens[last]
+	entries := oldest.Drain()
+	copy(c.gens[1:], c.gens[:last])
+	c.gens[0] = oldest
+	c.lock.Unlock()
+	if c.onEvictedCB != nil {
+		for _, ent := range entries {
+			c.onEvictedCB(ent.Key, ent.Value)
+		}
+	}
+	return len(entries)
+}
+
+// Len returns the number of items in all generations.
+func (c *GenerationalLRU[Key, Value]) Len() (length int) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for _, gen := range c.gens {
+		length += gen.Len()
+	}
+	return length
+}
+
+// Purge is used to completely clear the cache, evicting the entries of
+// every generation.
+func (c *GenerationalLRU[Key, Value]) Purge() {
+	var entries []simplelru.Entry[Key, Value]
+	c.lock.Lock()
+	for i := len(c.gens) - 1; i >= 0; i-- {
+		entries = append(entries, c.gens[i].Drain()...)
+	}
+	c.lock.Unlock()
+	if c.onEvictedCB != nil {
+		for _, ent := range entries {
+			c.onEvictedCB(ent.Key, ent.Value)
+		}
+	}
+}
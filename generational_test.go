@@ -0,0 +1,93 @@
+package lru
+
+import "testing"
+
+func TestGenerationalLRU(t *testing.T) {
+	var evicted []int
+	l, err := NewGenerationalLRU(4, 2, func(k, v int) {
+		evicted = append(evicted, k)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add(1, 1)
+	l.Add(2, 2)
+	if n := l.Rotate(); n != 0 {
+		t.Fatalf("bad evicted: %v", n)
+	}
+	l.Add(3, 3)
+	if l.Len() != 3 || !l.Contains(1) {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+
+	// 1 is moved into the current generation, and survives the rotation
+	// that drops the generation of 2
+	if v, ok := l.Get(1); !ok || v != 1 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	if n := l.Rotate(); n != 1 {
+		t.Fatalf("bad evicted: %v", n)
+	}
+	if len(evicted) != 1 || evicted[0] != 2 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	if l.Contains(2) || !l.Contains(1) || !l.Contains(3) {
+		t.Fatalf("bad contents")
+	}
+
+	// Peek does not move keys between generations
+	if v, ok := l.Peek(1); !ok || v != 1 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	l.Rotate()
+	if len(evicted) != 3 || evicted[1] != 3 || evicted[2] != 1 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	if l.Len() != 0 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+}
+
+func TestGenerationalLRU_Evict(t *testing.T) {
+	var evicted []int
+	l, err := NewGenerationalLRU(2, 3, func(k, v int) {
+		evicted = append(evicted, k)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// the current generation evicts like an LRU
+	l.Add(1, 1)
+	l.Add(2, 2)
+	if !l.Add(3, 3) {
+		t.Fatalf("should evict")
+	}
+	if len(evicted) != 1 || evicted[0] != 1 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+
+	// adding a key held by an older generation moves it
+	l.Rotate()
+	l.Add(2, 20)
+	l.Rotate()
+	if v, _ := l.Peek(2); v != 20 || l.Len() != 2 {
+		t.Fatalf("bad: %v %v", v, l.Len())
+	}
+
+	if !l.Remove(3) || l.Remove(3) {
+		t.Fatalf("bad remove")
+	}
+	l.Purge()
+	if l.Len() != 0 || len(evicted) != 3 || evicted[2] != 2 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+
+	if _, err := NewGenerationalLRU[int, int](0, 2, nil); err == nil {
+		t.Fatalf("should fail with invalid size")
+	}
+	if _, err := NewGenerationalLRU[int, int](2, 0, nil); err == nil {
+		t.Fatalf("should fail with invalid generations")
+	}
+}
@@ -0,0 +1,118 @@
+package lru
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func BenchmarkKeyInterning(b *testing.B) {
+	const caches, keys = 8, 10000
+	run := func(b *testing.B, opts ...Option[string, int]) {
+		var retained uint64
+		for n := 0; n < b.N; n++ {
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+			ls := make([]*Cache[string, int], caches)
+			for i := range ls {
+				ls[i], _ = NewWithOptions(keys, nil, opts...)
+				for k := 0; k < keys; k++ {
+					ls[i].Add(fmt.Sprintf("%064d", k), k)
+				}
+			}
+			runtime.GC()
+			runtime.ReadMemStats(&after)
+			retained += after.HeapAlloc - before.HeapAlloc
+			for _, l := range ls {
+				l.Purge()
+			}
+			runtime.KeepAlive(ls)
+		}
+		b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+	}
+	b.Run("plain", func(b *testing.B) {
+		run(b)
+	})
+	b.Run("interned", func(b *testing.B) {
+		run(b, WithKeyInterning[int](NewKeyInternPool()))
+	})
+}
+
+func TestLRUKeyInterning(t *testing.T) {
+	pool := NewKeyInternPool()
+	l1, err := NewWithOptions(2, nil, WithKeyInterning[int](pool))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l2, err := NewWithOptions(2, nil, WithKeyInterning[int](pool))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l1.Add(strings.Repeat("a", 8), 1)
+	l2.Add(strings.Repeat("a", 8), 1)
+	l1.Add(strings.Repeat("a", 8), 2) // update, does not take a reference
+	if n := pool.len(); n != 1 {
+		t.Fatalf("bad pool size: %v", n)
+	}
+	if v, ok := l2.Get("aaaaaaaa"); !ok || v != 1 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+
+	l1.Add("b", 1)
+	l1.Add("c", 1) // evicts "aaaaaaaa" from l1 only
+	if n := pool.len(); n != 3 {
+		t.Fatalf("bad pool size: %v", n)
+	}
+	l2.Remove("aaaaaaaa")
+	if n := pool.len(); n != 2 {
+		t.Fatalf("bad pool size: %v", n)
+	}
+
+	l1.Drain()
+	if n := pool.len(); n != 0 {
+		t.Fatalf("bad pool size: %v", n)
+	}
+}
+
+func TestLRUKeyInterning_Close(t *testing.T) {
+	pool := NewKeyInternPool()
+	l1, err := NewWithOptions(4, nil, WithKeyInterning[int](pool))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l2, err := NewWithOptions(4, nil, WithKeyInterning[int](pool))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l1.Add("a", 1)
+	l1.Add("b", 1)
+	l2.Add("a", 1)
+
+	// closing releases the references of l1 only
+	l1.Close()
+	if n := pool.len(); n != 1 {
+		t.Fatalf("bad pool size: %v", n)
+	}
+	l1.Close()
+	if n := pool.len(); n != 1 {
+		t.Fatalf("bad pool size: %v", n)
+	}
+
+	// l1 stays usable, without interning
+	l1.Add("c", 1)
+	l1.Remove("a")
+	if n := pool.len(); n != 1 {
+		t.Fatalf("bad pool size: %v", n)
+	}
+	l2.Purge()
+	if n := pool.len(); n != 0 {
+		t.Fatalf("bad pool size: %v", n)
+	}
+
+	if _, err := NewWithOptions(4, nil, WithKeyInterning[int](nil)); err == nil {
+		t.Fatalf("should fail without pool")
+	}
+}
@@ -0,0 +1,28 @@
+package lru
+
+import (
+	"encoding/gob"
+	"io"
+)
+
+// DumpKeys writes the keys in the cache, from oldest to newest, to w. Values
+// are not written, which makes this a lightweight way to persist the working
+// set of a cache whose values are cheap to recompute: after a restart, the
+// keys read back with LoadKeys can be fetched and added in order to rebuild
+// the cache with the same recency order.
+//
+// Keys are encoded with encoding/gob, so the key type must be encodable by
+// it: structs need exported fields, and interface keys need their concrete
+// types registered with gob.Register.
+func (c *Cache[Key, Value]) DumpKeys(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(c.Keys())
+}
+
+// LoadKeys reads the keys written by DumpKeys from r, from oldest to newest.
+func LoadKeys[Key comparable](r io.Reader) ([]Key, error) {
+	var keys []Key
+	if err := gob.NewDecoder(r).Decode(&keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
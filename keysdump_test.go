@@ -0,0 +1,55 @@
+package lru
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLRUDumpKeys(t *testing.T) {
+	l, err := New[string, int](4)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for _, k := range []string{"a", "b", "c", "d"} {
+		l.Add(k, len(k))
+	}
+	l.Get("a")
+
+	var buf bytes.Buffer
+	if err = l.DumpKeys(&buf); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	keys, err := LoadKeys[string](&buf)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	expected := []string{"b", "c", "d", "a"}
+	if len(keys) != len(expected) {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	for i, k := range keys {
+		if k != expected[i] {
+			t.Fatalf("bad keys: %v", keys)
+		}
+	}
+
+	// re-adding in order restores the recency order
+	l2, err := New[string, int](4)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for _, k := range keys {
+		l2.Add(k, len(k))
+	}
+	for i, k := range l2.Keys() {
+		if k != expected[i] {
+			t.Fatalf("bad keys: %v", l2.Keys())
+		}
+	}
+}
+
+func TestLRULoadKeys_Invalid(t *testing.T) {
+	if _, err := LoadKeys[string](bytes.NewBufferString("garbage")); err == nil {
+		t.Fatalf("should fail on invalid input")
+	}
+}
@@ -0,0 +1,39 @@
+package lru
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrKeyTooLarge is returned by TryAdd for keys rejected by the limit set
+// with WithMaxKeySize.
+var ErrKeyTooLarge = errors.New("key exceeds the maximum key size")
+
+// WithMaxKeySize makes the cache reject keys whose size, as measured by
+// size, exceeds maxSize, e.g. to keep a cache keyed by untrusted strings
+// from being flooded with huge keys. Rejected keys are simply not cached:
+// Add, ContainsOrAdd and PeekOrAdd store nothing and report no eviction,
+// AddPending reserves nothing, and TryAdd returns ErrKeyTooLarge. Callers
+// must handle the data of rejected keys some other way.
+//
+// size runs inside the critical section of every adding operation, so it
+// must be fast and must not call methods of the cache.
+func WithMaxKeySize[Key comparable, Value any](size func(Key) int, maxSize int) Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		if size == nil {
+			return fmt.Errorf("must provide a key size function")
+		}
+		if maxSize < 0 {
+			return fmt.Errorf("must provide a non-negative max key size")
+		}
+		c.keySize = size
+		c.maxKeySize = maxSize
+		return nil
+	}
+}
+
+// keyTooLarge reports whether the key must be rejected. The caller must hold
+// the lock.
+func (c *Cache[Key, Value]) keyTooLarge(key Key) bool {
+	return c.keySize != nil && c.keySize(key) > c.maxKeySize
+}
@@ -0,0 +1,51 @@
+package lru
+
+import "testing"
+
+func TestWithMaxKeySize(t *testing.T) {
+	strLen := func(k string) int { return len(k) }
+	l, err := NewWithOptions[string, int](4, nil, WithMaxKeySize[string, int](strLen, 3))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add("abc", 1)
+	l.Add("abcd", 2)
+	if !l.Contains("abc") || l.Contains("abcd") {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+	if ok, _ := l.ContainsOrAdd("abcd", 2); ok || l.Contains("abcd") {
+		t.Fatalf("should reject oversized key")
+	}
+	if _, ok, _ := l.PeekOrAdd("abcd", 2); ok || l.Contains("abcd") {
+		t.Fatalf("should reject oversized key")
+	}
+	fill, already := l.AddPending("abcd")
+	fill(2)
+	if already || l.Contains("abcd") {
+		t.Fatalf("should reject oversized key")
+	}
+	if _, ok := l.Get("abcd"); ok {
+		t.Fatalf("should miss")
+	}
+
+	if _, err := l.TryAdd("abcd", 2); err != ErrKeyTooLarge {
+		t.Fatalf("bad err: %v", err)
+	}
+	if _, err := l.TryAdd("ab", 2); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if l.Len() != 2 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+}
+
+func TestWithMaxKeySize_Invalid(t *testing.T) {
+	if _, err := NewWithOptions[string, int](4, nil, WithMaxKeySize[string, int](nil, 3)); err == nil {
+		t.Fatalf("should fail without size function")
+	}
+	strLen := func(k string) int { return len(k) }
+	if _, err := NewWithOptions[string, int](4, nil, WithMaxKeySize[string, int](strLen, -1)); err == nil {
+		t.Fatalf("should fail with negative max size")
+	}
+}
@@ -0,0 +1,94 @@
+package lru
+
+import "github.com/errorhandler/golang-lru/simplelru"
+
+// WithPerKeyStats makes the cache count hits and misses of Get and Query
+// separately for every key, for offline analysis of which keys benefit from
+// caching. The counts are read back with KeyStats.
+//
+// The counts of keys in the cache are kept alongside their entries, costing
+// a map entry and two counters per entry. Misses are mostly recorded for
+// keys that are not in the cache, so these, as well as the counts of evicted
+// keys, are kept in a separate LRU bounded to the size of the cache: the
+// counts of the least recently missed absent keys are eventually dropped.
+// When an absent key is added, its counts move back alongside its entry.
+func WithPerKeyStats[Key comparable, Value any]() Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		c.keyStats = &keyStats[Key]{}
+		return nil
+	}
+}
+
+// keyStat holds the counts of a single key.
+type keyStat struct {
+	hits   uint64
+	misses uint64
+}
+
+// keyStats holds the counts of all keys. It is protected by the cache lock.
+type keyStats[Key comparable] struct {
+	present map[Key]*keyStat
+	absent  *simplelru.LRU[Key, keyStat]
+}
+
+// init allocates the structures, sizing the absent keys LRU like the cache.
+func (s *keyStats[Key]) init(size int) (err error) {
+	s.present = make(map[Key]*keyStat)
+	s.absent, err = simplelru.NewLRU[Key, keyStat](size, nil)
+	return err
+}
+
+// record counts a lookup of the key.
+func (s *keyStats[Key]) record(key Key, hit bool) {
+	if st, ok := s.present[key]; ok {
+		if hit {
+			st.hits++
+		} else {
+			st.misses++
+		}
+		return
+	}
+	st, _ := s.absent.Peek(key)
+	if hit {
+		st.hits++
+	} else {
+		st.misses++
+	}
+	s.absent.Add(key, st)
+}
+
+// admit moves the counts of a key that is about to be added alongside the
+// other present keys.
+func (s *keyStats[Key]) admit(key Key) {
+	if _, ok := s.present[key]; ok {
+		return
+	}
+	st, _ := s.absent.Peek(key)
+	s.absent.Remove(key)
+	s.present[key] = &st
+}
+
+// evict moves the counts of a key that left the cache to the absent keys.
+func (s *keyStats[Key]) evict(key Key) {
+	if st, ok := s.present[key]; ok {
+		delete(s.present, key)
+		s.absent.Add(key, *st)
+	}
+}
+
+// KeyStats returns the number of hits and misses recorded for the key by
+// Get and Query. ok is false if the cache was not created with
+// WithPerKeyStats, or if there are no counts for the key, either because it
+// was never looked up or because its counts were dropped while absent.
+func (c *Cache[Key, Value]) KeyStats(key Key) (hits, misses uint64, ok bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.keyStats == nil {
+		return 0, 0, false
+	}
+	if st, ok := c.keyStats.present[key]; ok {
+		return st.hits, st.misses, true
+	}
+	st, ok := c.keyStats.absent.Peek(key)
+	return st.hits, st.misses, ok
+}
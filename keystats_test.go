@@ -0,0 +1,80 @@
+package lru
+
+import "testing"
+
+func TestLRUKeyStats(t *testing.T) {
+	l, err := NewWithOptions[int, int](2, nil, WithPerKeyStats[int, int]())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if _, _, ok := l.KeyStats(1); ok {
+		t.Fatalf("should have no stats")
+	}
+
+	// misses of absent keys are recorded
+	l.Get(1)
+	l.Get(1)
+	if hits, misses, ok := l.KeyStats(1); !ok || hits != 0 || misses != 2 {
+		t.Fatalf("bad stats: %v %v %v", hits, misses, ok)
+	}
+
+	// and carried over once the key is added
+	l.Add(1, 1)
+	l.Get(1)
+	l.Query([]int{1, 2})
+	if hits, misses, ok := l.KeyStats(1); !ok || hits != 2 || misses != 2 {
+		t.Fatalf("bad stats: %v %v %v", hits, misses, ok)
+	}
+	if hits, misses, ok := l.KeyStats(2); !ok || hits != 0 || misses != 1 {
+		t.Fatalf("bad stats: %v %v %v", hits, misses, ok)
+	}
+
+	// Peek and Contains are not recorded
+	l.Peek(1)
+	l.Contains(1)
+	if hits, _, _ := l.KeyStats(1); hits != 2 {
+		t.Fatalf("bad hits: %v", hits)
+	}
+
+	// evicted keys keep their stats
+	l.Add(2, 2)
+	l.Add(3, 3)
+	if l.Contains(1) {
+		t.Fatalf("should be evicted")
+	}
+	l.Get(1)
+	if hits, misses, ok := l.KeyStats(1); !ok || hits != 2 || misses != 3 {
+		t.Fatalf("bad stats: %v %v %v", hits, misses, ok)
+	}
+	if len(l.keyStats.present) != 2 {
+		t.Fatalf("bad present stats: %v", l.keyStats.present)
+	}
+
+	// absent keys are bounded by the cache size
+	for i := 10; i < 20; i++ {
+		l.Get(i)
+	}
+	if _, _, ok := l.KeyStats(1); ok {
+		t.Fatalf("stats should be dropped")
+	}
+	if l.keyStats.absent.Len() != 2 {
+		t.Fatalf("bad absent stats: %v", l.keyStats.absent.Len())
+	}
+
+	l.Purge()
+	if len(l.keyStats.present) != 0 {
+		t.Fatalf("bad present stats: %v", l.keyStats.present)
+	}
+}
+
+func TestLRUKeyStats_Disabled(t *testing.T) {
+	l, err := New[int, int](2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Get(1)
+	if _, _, ok := l.KeyStats(1); ok {
+		t.Fatalf("should have no stats")
+	}
+}
@@ -0,0 +1,83 @@
+package lru
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithLockInstrumentation makes the cache count the acquisitions of its
+// lock that had to wait, and the total time spent waiting, as reported by
+// LockStats, e.g. to decide whether a cache is contended enough to be
+// split.
+//
+// Each acquisition first tries to take the lock without waiting, and only
+// counts as contended if that fails, so the counts are an approximation: an
+// acquisition that fails the try but gets the lock right after is counted,
+// with a short wait. Uncontended acquisitions cost about the same, while
+// contended ones also fail a try, read the clock twice and update two
+// atomic counters shared by all callers.
+func WithLockInstrumentation[Key comparable, Value any]() Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		c.lock.stats = &lockStats{}
+		return nil
+	}
+}
+
+// cacheLock is the lock of a cache, which counts the contended
+// acquisitions when stats is set.
+type cacheLock struct {
+	sync.RWMutex
+	stats *lockStats
+}
+
+// lockStats holds the counts of contended acquisitions. It is allocated
+// separately for 64-bit alignment of the atomically accessed counters.
+type lockStats struct {
+	contended uint64
+	wait      int64
+}
+
+// Lock locks the lock for writing.
+func (l *cacheLock) Lock() {
+	if l.stats == nil {
+		l.RWMutex.Lock()
+		return
+	}
+	if l.RWMutex.TryLock() {
+		return
+	}
+	start := time.Now()
+	l.RWMutex.Lock()
+	l.stats.record(time.Since(start))
+}
+
+// RLock locks the lock for reading.
+func (l *cacheLock) RLock() {
+	if l.stats == nil {
+		l.RWMutex.RLock()
+		return
+	}
+	if l.RWMutex.TryRLock() {
+		return
+	}
+	start := time.Now()
+	l.RWMutex.RLock()
+	l.stats.record(time.Since(start))
+}
+
+// record counts a contended acquisition that waited for d.
+func (s *lockStats) record(d time.Duration) {
+	atomic.AddUint64(&s.contended, 1)
+	atomic.AddInt64(&s.wait, int64(d))
+}
+
+// LockStats returns the number of acquisitions of the cache lock that had
+// to wait, and the total time they waited. Both are always 0 unless the
+// cache was created with WithLockInstrumentation.
+func (c *Cache[Key, Value]) LockStats() (contended uint64, totalWait time.Duration) {
+	if c.lock.stats == nil {
+		return 0, 0
+	}
+	return atomic.LoadUint64(&c.lock.stats.contended), time.Duration(atomic.LoadInt64(&c.lock.stats.wait))
+}
@@ -0,0 +1,57 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithLockInstrumentation(t *testing.T) {
+	l, err := NewWithOptions(128, nil, WithLockInstrumentation[int, int]())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// hold the lock so that the writers below have to wait
+	l.lock.Lock()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l.Add(i, i)
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	l.lock.Unlock()
+	wg.Wait()
+
+	contended, totalWait := l.LockStats()
+	if contended == 0 {
+		t.Fatalf("bad contended: %v", contended)
+	}
+	if totalWait <= 0 {
+		t.Fatalf("bad total wait: %v", totalWait)
+	}
+	if l.Len() != 4 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+
+	// uncontended acquisitions are not counted
+	l.Get(0)
+	l.Peek(0)
+	if c, _ := l.LockStats(); c != contended {
+		t.Fatalf("bad contended: %v", c)
+	}
+}
+
+func TestLockStats_Disabled(t *testing.T) {
+	l, err := New[int, int](8)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, 1)
+	if contended, totalWait := l.LockStats(); contended != 0 || totalWait != 0 {
+		t.Fatalf("bad: %v %v", contended, totalWait)
+	}
+}
@@ -0,0 +1,117 @@
+package lru
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// lockMinBackoff and lockMaxBackoff bound the pause between attempts
+	// to take the lock within the timeout set with WithLockTimeout.
+	lockMinBackoff = 10 * time.Microsecond
+	lockMaxBackoff = time.Millisecond
+)
+
+// ErrBusy is returned by the Try methods when the lock could not be taken
+// within the timeout set with WithLockTimeout.
+var ErrBusy = errors.New("cache is busy")
+
+// WithLockTimeout makes TryGet, TryPeek, TryAdd and TryRemove give up with
+// ErrBusy if they cannot take the cache lock within d, so that latency
+// sensitive callers fail fast instead of queueing behind slow operations.
+// The other methods are unaffected and always wait for the lock.
+//
+// The lock is polled rather than queued for, so under sustained contention
+// the Try methods may fail even though waiting slightly longer than d, or
+// waiting in line like the other methods, would have succeeded.
+func WithLockTimeout[Key comparable, Value any](d time.Duration) Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		if d <= 0 {
+			return fmt.Errorf("must provide a positive lock timeout")
+		}
+		c.lockTimeout = d
+		return nil
+	}
+}
+
+// acquire takes the lock with lock, or by polling tryLock until the lock
+// timeout expires if one is set.
+func (c *Cache[Key, Value]) acquire(tryLock func() bool, lock func()) error {
+	if c.lockTimeout <= 0 {
+		lock()
+		return nil
+	}
+	if tryLock() {
+		return nil
+	}
+	deadline := time.Now().Add(c.lockTimeout)
+	backoff := lockMinBackoff
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return ErrBusy
+		}
+		if backoff > remaining {
+			backoff = remaining
+		}
+		time.Sleep(backoff)
+		if tryLock() {
+			return nil
+		}
+		if backoff < lockMaxBackoff {
+			backoff *= 2
+		}
+	}
+}
+
+// TryGet is like Get, but returns ErrBusy if the lock could not be taken
+// within the timeout set with WithLockTimeout. Waiting for a pending entry
+// is not bounded by the timeout.
+func (c *Cache[Key, Value]) TryGet(key Key) (value Value, ok bool, err error) {
+	if err = c.acquire(c.lock.TryLock, c.lock.Lock); err != nil {
+		return value, false, err
+	}
+	value, ok = c.getAndUnlock(key)
+	return value, ok, nil
+}
+
+// TryPeek is like Peek, but returns ErrBusy if the lock could not be taken
+// within the timeout set with WithLockTimeout.
+func (c *Cache[Key, Value]) TryPeek(key Key) (value Value, ok bool, err error) {
+	if err = c.acquire(c.lock.TryRLock, c.lock.RLock); err != nil {
+		return value, false, err
+	}
+	value, ok = c.peek(key)
+	c.lock.RUnlock()
+	return value, ok, nil
+}
+
+// TryAdd is like Add, but returns ErrBusy if the lock could not be taken
+// within the timeout set with WithLockTimeout, ErrKeyTooLarge if the key is
+// rejected by the limit set with WithMaxKeySize, and ErrZeroKey if the key
+// is rejected by WithRejectZeroKey.
+func (c *Cache[Key, Value]) TryAdd(key Key, value Value) (evicted bool, err error) {
+	if err = c.acquire(c.lock.TryLock, c.lock.Lock); err != nil {
+		return false, err
+	}
+	if c.keyTooLarge(key) {
+		c.lock.Unlock()
+		return false, ErrKeyTooLarge
+	}
+	if c.zeroKeyRejected(key) {
+		c.lock.Unlock()
+		return false, ErrZeroKey
+	}
+	return c.addAndUnlock(key, value), nil
+}
+
+// TryRemove is like Remove, but returns ErrBusy if the lock could not be
+// taken within the timeout set with WithLockTimeout.
+func (c *Cache[Key, Value]) TryRemove(key Key) (present bool, err error) {
+	if err = c.acquire(c.lock.TryLock, c.lock.Lock); err != nil {
+		return false, err
+	}
+	_, present = c.removeAndUnlock(key)
+	return present, nil
+}
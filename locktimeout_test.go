@@ -0,0 +1,57 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithLockTimeout(t *testing.T) {
+	l, err := NewWithOptions[int, int](4, nil, WithLockTimeout[int, int](10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if _, err := l.TryAdd(1, 1); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if v, ok, err := l.TryGet(1); err != nil || !ok || v != 1 {
+		t.Fatalf("bad: %v %v %v", v, ok, err)
+	}
+	if v, ok, err := l.TryPeek(1); err != nil || !ok || v != 1 {
+		t.Fatalf("bad: %v %v %v", v, ok, err)
+	}
+
+	// hold the lock past the timeout
+	l.lock.Lock()
+	start := time.Now()
+	if _, _, err := l.TryGet(1); err != ErrBusy {
+		t.Fatalf("bad err: %v", err)
+	}
+	if time.Since(start) < 10*time.Millisecond {
+		t.Fatalf("should wait for the timeout")
+	}
+	if _, _, err := l.TryPeek(1); err != ErrBusy {
+		t.Fatalf("bad err: %v", err)
+	}
+	if _, err := l.TryAdd(2, 2); err != ErrBusy {
+		t.Fatalf("bad err: %v", err)
+	}
+	if _, err := l.TryRemove(1); err != ErrBusy {
+		t.Fatalf("bad err: %v", err)
+	}
+
+	// the lock is taken if released within the timeout
+	time.AfterFunc(2*time.Millisecond, l.lock.Unlock)
+	if present, err := l.TryRemove(1); err != nil || !present {
+		t.Fatalf("bad: %v %v", present, err)
+	}
+	if l.Len() != 0 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+}
+
+func TestWithLockTimeout_Invalid(t *testing.T) {
+	if _, err := NewWithOptions[int, int](4, nil, WithLockTimeout[int, int](0)); err == nil {
+		t.Fatalf("should fail with non-positive timeout")
+	}
+}
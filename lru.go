@@ -1,7 +1,8 @@
 package lru
 
 import (
-	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/errorhandler/golang-lru/simplelru"
 )
@@ -13,11 +14,105 @@ const (
 
 // Cache is a thread-safe fixed size LRU cache.
 type Cache[Key comparable, Value any] struct {
+	// approxLen mirrors the length of lru for LenApprox. It is accessed
+	// atomically and kept first for 64-bit alignment on 32-bit platforms.
+	approxLen int64
+
+	// evictions counts the entries evicted to make room since the last
+	// TakeEvictionCount. It is accessed atomically and kept next to
+	// approxLen for 64-bit alignment.
+	evictions uint64
+
+	// hits, misses and totalEvictions are the running counts reported by
+	// Stats. They are accessed atomically and kept next to evictions for
+	// 64-bit alignment.
+	hits           uint64
+	misses         uint64
+	totalEvictions uint64
+
 	lru         *simplelru.LRU[Key, Value]
 	evictedKeys []Key
 	evictedVals []Value
 	onEvictedCB func(k Key, v Value)
-	lock        sync.RWMutex
+	lock        cacheLock
+
+	// promotionPaused is set between PausePromotion and ResumePromotion.
+	promotionPaused bool
+
+	// keyStats is only set when created with WithPerKeyStats.
+	keyStats *keyStats[Key]
+
+	// internKey and releaseKey are only set when created with
+	// WithKeyInterning.
+	internKey  func(Key) Key
+	releaseKey func(Key)
+
+	// evictionRate is only set when created with WithEvictionRateWindow.
+	evictionRate *evictionRate
+
+	// pending holds the entries reserved by AddPending until they are
+	// filled.
+	pending map[Key]*pendingValue[Value]
+
+	// candidateHook is only set when created with WithCandidateChangeHook,
+	// and is called when the oldest entry differs from candidate.
+	candidateHook func(oldCandidate, newCandidate Key)
+	candidate     Key
+	hasCandidate  bool
+
+	// saturationHook is only set when created with WithSaturationHook, and
+	// is called when the cache becomes full or stops being full.
+	saturationHook func(full bool)
+	saturated      bool
+
+	// evictBatcher is only set when created with WithBatchedEvictions.
+	evictBatcher *evictBatcher[Key, Value]
+
+	// evictTransform is only set when created with WithEvictTransform.
+	evictTransform func(Key, Value) Value
+
+	// rejectZeroKey is only set when created with WithRejectZeroKey.
+	rejectZeroKey bool
+
+	// keySize is only set when created with WithMaxKeySize, and rejects
+	// keys whose size exceeds maxKeySize.
+	keySize    func(Key) int
+	maxKeySize int
+
+	// getValidator is only set when created with WithGetValidator.
+	getValidator func(Value) bool
+
+	// lockTimeout is only set when created with WithLockTimeout, and bounds
+	// the time the Try methods wait for the lock.
+	lockTimeout time.Duration
+
+	// generations is only set when created with WithGenerations.
+	generations *generations[Key]
+
+	// timestamps is only set when created with WithTimestamps.
+	timestamps *timestamps[Key]
+
+	// admission is only set when created with WithSecondChanceAdmission.
+	admission *doorkeeper[Key]
+
+	// asyncEvict is only set when created with WithAsyncEvict, and then
+	// receives the evictions through onEvictedCB.
+	asyncEvict *asyncEvictPool[Key, Value]
+
+	// overflow is only set when created with WithOverflowBuffer.
+	overflow *overflowBuffer[Key, Value]
+
+	// collisions is only set when created with WithCollisionDetection.
+	collisions *collisionDetector[Key]
+
+	// now is the clock of the time-based options, set with WithClock.
+	now func() time.Time
+
+	// writeThrottle is only set when created with WithWriteThrottle.
+	writeThrottle *writeThrottle[Key]
+
+	// warmup is only set when created with WithWarmup.
+	warmup *warmup
 }
 
 // New creates an LRU of the given size.
@@ -28,15 +123,100 @@ func New[Key comparable, Value any](size int) (*Cache[Key, Value], error) {
 // NewWithEvict constructs a fixed size cache with the given eviction
 // callback.
 func NewWithEvict[Key comparable, Value any](size int, onEvicted func(key Key, value Value)) (c *Cache[Key, Value], err error) {
+	return NewWithOptions(size, onEvicted)
+}
+
+// Option configures optional behavior of a Cache created with
+// NewWithOptions.
+type Option[Key comparable, Value any] func(c *Cache[Key, Value]) error
+
+// NewWithOptions constructs a fixed size cache with the given eviction
+// callback, which may be nil, and options.
+func NewWithOptions[Key comparable, Value any](size int, onEvicted func(key Key, value Value), opts ...Option[Key, Value]) (c *Cache[Key, Value], err error) {
 	// create a cache with default settings
 	c = &Cache[Key, Value]{
 		onEvictedCB: onEvicted,
 	}
+	for _, opt := range opts {
+		if err = opt(c); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	if c.now == nil {
+		c.now = time.Now
+	}
+	if c.evictionRate != nil {
+		c.evictionRate.now = c.now
+	}
 	if onEvicted != nil {
 		c.initEvictBuffers()
-		onEvicted = c.onEvicted
 	}
-	c.lru, err = simplelru.NewLRU(size, onEvicted)
+	lruSize := size
+	if c.warmup != nil && size > 0 {
+		c.warmup.size = size
+		c.warmup.until = c.now().Add(c.warmup.duration)
+		lruSize += c.warmup.margin
+	}
+	if c.lru, err = simplelru.NewLRU(lruSize, c.onEvicted); err != nil {
+		c.Close()
+		return nil, err
+	}
+	if c.keyStats != nil {
+		if err = c.keyStats.init(size); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// EvictContext is a restricted handle on a Cache that is passed to eviction
+// callbacks registered with NewWithEvictContext. It operates on the cache
+// while its lock is already held, so the callback can inspect and remove
+// further entries, e.g. to cascade an eviction to related entries.
+type EvictContext[Key comparable, Value any] interface {
+	// Peek returns the key value without updating the "recently used"-ness
+	// of the key.
+	Peek(key Key) (value Value, ok bool)
+
+	// Remove removes the provided key from the cache, returning if the key
+	// was contained. The eviction callback is invoked again for the removed
+	// entry before Remove returns.
+	Remove(key Key) (present bool)
+}
+
+// evictContext implements EvictContext on top of the locked LRU.
+type evictContext[Key comparable, Value any] struct {
+	lru *simplelru.LRU[Key, Value]
+}
+
+func (e evictContext[Key, Value]) Peek(key Key) (value Value, ok bool) {
+	return e.lru.Peek(key)
+}
+
+func (e evictContext[Key, Value]) Remove(key Key) (present bool) {
+	return e.lru.Remove(key)
+}
+
+// NewWithEvictContext constructs a fixed size cache with the given eviction
+// callback. Unlike with NewWithEvict, the callback is invoked inside the
+// critical section, together with the operation that caused the eviction,
+// and is passed an EvictContext through which it can safely access the
+// cache. The callback must not call methods of the Cache itself, which
+// would deadlock. Adding entries is deliberately not possible, as an Add
+// could trigger further evictions and recurse without bound; each cascaded
+// Remove shrinks the cache, so cascades always terminate.
+func NewWithEvictContext[Key comparable, Value any](size int, onEvicted func(ctx EvictContext[Key, Value], key Key, value Value)) (c *Cache[Key, Value], err error) {
+	if onEvicted == nil {
+		return New[Key, Value](size)
+	}
+	c = &Cache[Key, Value]{now: time.Now}
+	var ctx evictContext[Key, Value]
+	ctx.lru, err = simplelru.NewLRU(size, func(key Key, value Value) {
+		onEvicted(ctx, key, value)
+	})
+	c.lru = ctx.lru
 	return
 }
 
@@ -45,11 +225,195 @@ func (c *Cache[Key, Value]) initEvictBuffers() {
 	c.evictedVals = make([]Value, 0, DefaultEvictedBufferSize)
 }
 
+// takeEvicted returns and resets the evictions saved for the eviction
+// callback. The caller must hold the lock.
+func (c *Cache[Key, Value]) takeEvicted() (ks []Key, vs []Value) {
+	if c.onEvictedCB == nil || len(c.evictedKeys) == 0 {
+		return nil, nil
+	}
+	ks, vs = c.evictedKeys, c.evictedVals
+	c.initEvictBuffers()
+	return ks, vs
+}
+
+// notifyEvicted invokes the eviction callback, outside of critical section.
+func (c *Cache[Key, Value]) notifyEvicted(ks []Key, vs []Value) {
+	for i := 0; i < len(ks); i++ {
+		c.onEvictedCB(ks[i], vs[i])
+	}
+}
+
+// get looks up a key's value, promoting it unless promotion is paused.
+// Entries that fail the WithGetValidator check are removed, and their
+// evictions must be delivered with takeEvicted once the lock is released.
+// The caller must hold the lock.
+func (c *Cache[Key, Value]) get(key Key) (value Value, ok bool) {
+	if c.promotionPaused {
+		value, ok = c.lru.Peek(key)
+	} else {
+		value, ok = c.lru.Get(key)
+	}
+	if !ok && c.overflow != nil {
+		value, ok = c.overflow.get(key)
+	} else if ok && !c.validate(key, value) {
+		var zeroValue Value
+		value, ok = zeroValue, false
+	}
+	if ok {
+		atomic.AddUint64(&c.hits, 1)
+	} else {
+		atomic.AddUint64(&c.misses, 1)
+	}
+	if c.keyStats != nil {
+		c.keyStats.record(key, ok)
+	}
+	c.checkCandidate()
+	return value, ok
+}
+
+// add adds a value as the newest entry, or as the oldest one while
+// promotion is paused. Keys rejected by WithMaxKeySize or
+// WithRejectZeroKey, or filtered by WithSecondChanceAdmission, are not
+// added, and new keys are staged while the overflow buffer of
+// WithOverflowBuffer is in use. The caller must hold the lock.
+func (c *Cache[Key, Value]) add(key Key, value Value) (evicted bool) {
+	if c.keyTooLarge(key) || c.zeroKeyRejected(key) || !c.admits(key) {
+		return false
+	}
+	if c.throttleAdd(key, value) {
+		return false
+	}
+	if c.stages(key) {
+		return c.stage(key, value)
+	}
+	return c.addMain(key, value)
+}
+
+// addMain implements add for keys that are not staged in the overflow
+// buffer. The caller must hold the lock.
+func (c *Cache[Key, Value]) addMain(key Key, value Value) (evicted bool) {
+	if c.keyStats != nil {
+		c.keyStats.admit(key)
+	}
+	if c.collisions != nil {
+		c.collisions.check(key)
+	}
+	if c.internKey != nil && !c.lru.Contains(key) {
+		key = c.internKey(key)
+	}
+	if c.promotionPaused {
+		evicted = c.lru.AddToBack(key, value)
+	} else {
+		evicted = c.lru.Add(key, value)
+	}
+	if c.generations != nil {
+		c.generations.stamp(key)
+	}
+	if c.timestamps != nil {
+		c.timestamps.stamp(key, c.now())
+	}
+	if c.writeThrottle != nil {
+		c.writeThrottle.last[key] = c.now()
+	}
+	if evicted {
+		atomic.AddUint64(&c.evictions, 1)
+		atomic.AddUint64(&c.totalEvictions, 1)
+		if c.evictionRate != nil {
+			c.evictionRate.record()
+		}
+	}
+	if p, ok := c.pending[key]; ok {
+		c.resolvePending(key, p, value)
+	}
+	c.storeLen()
+	c.checkCandidate()
+	return evicted
+}
+
+// replaceValue updates the value of a key in the cache in place, without
+// updating its recent-ness. The caller must hold the lock.
+func (c *Cache[Key, Value]) replaceValue(key Key, value Value) {
+	c.lru.AddToBack(key, value)
+	if c.generations != nil {
+		c.generations.stamp(key)
+	}
+	if c.timestamps != nil {
+		c.timestamps.stamp(key, c.now())
+	}
+}
+
+// forget drops the per-key state of a key that left the cache. The caller
+// must hold the lock.
+func (c *Cache[Key, Value]) forget(k Key) {
+	if c.keyStats != nil {
+		c.keyStats.evict(k)
+	}
+	if c.releaseKey != nil {
+		c.releaseKey(k)
+	}
+	if c.generations != nil {
+		c.generations.evict(k)
+	}
+	if c.timestamps != nil {
+		c.timestamps.evict(k)
+	}
+	if c.collisions != nil {
+		c.collisions.evict(k)
+	}
+	if c.writeThrottle != nil {
+		delete(c.writeThrottle.last, k)
+	}
+	if p, ok := c.pending[k]; ok {
+		delete(c.pending, k)
+		close(p.done)
+	}
+}
+
 // onEvicted save evicted key/val and sent in externally registered callback
 // outside of critical section
 func (c *Cache[Key, Value]) onEvicted(k Key, v Value) {
-	c.evictedKeys = append(c.evictedKeys, k)
-	c.evictedVals = append(c.evictedVals, v)
+	// unfilled entries reserved by AddPending hold no value of the caller
+	_, unfilled := c.pending[k]
+	c.forget(k)
+	if !unfilled {
+		c.saveEvicted(k, v)
+	}
+}
+
+// saveEvicted saves an entry leaving the cache for the eviction callback
+// and the batched evictions, after applying the transform of
+// WithEvictTransform. The caller must hold the lock.
+func (c *Cache[Key, Value]) saveEvicted(k Key, v Value) {
+	if c.evictBatcher == nil && c.onEvictedCB == nil {
+		return
+	}
+	if c.evictTransform != nil {
+		v = c.evictTransform(k, v)
+	}
+	if c.evictBatcher != nil {
+		c.evictBatcher.add(k, v)
+	}
+	if c.onEvictedCB != nil {
+		c.evictedKeys = append(c.evictedKeys, k)
+		c.evictedVals = append(c.evictedVals, v)
+	}
+}
+
+// Close releases the background resources of options such as
+// WithBatchedEvictions and WithAsyncEvict, flushing any pending work first,
+// and the references of the cache to the pool of WithKeyInterning. It does
+// nothing for caches that do not use such options, and may be called
+// multiple times.
+func (c *Cache[Key, Value]) Close() {
+	c.lock.Lock()
+	c.releaseKeys()
+	c.lock.Unlock()
+	if c.evictBatcher != nil {
+		c.evictBatcher.close()
+	}
+	if c.asyncEvict != nil {
+		c.asyncEvict.close()
+	}
 }
 
 // Purge is used to completely clear the cache.
@@ -58,6 +422,13 @@ func (c *Cache[Key, Value]) Purge() {
 	var vs []Value
 	c.lock.Lock()
 	c.lru.Purge()
+	if c.overflow != nil {
+		for _, ent := range c.overflow.drain() {
+			c.evictedStaged(ent.Key, ent.Value)
+		}
+	}
+	c.storeLen()
+	c.checkCandidate()
 	if c.onEvictedCB != nil && len(c.evictedKeys) > 0 {
 		ks, vs = c.evictedKeys, c.evictedVals
 		c.initEvictBuffers()
@@ -71,36 +442,252 @@ func (c *Cache[Key, Value]) Purge() {
 	}
 }
 
+// Drain atomically removes all entries from the cache and returns them from
+// oldest to newest, e.g. to process them as a batch while new entries
+// accumulate in the now empty cache. Drained entries are not evictions, so
+// the eviction callback is not invoked for them. Pending entries reserved
+// with AddPending are dropped without being returned.
+func (c *Cache[Key, Value]) Drain() []simplelru.Entry[Key, Value] {
+	c.lock.Lock()
+	entries := c.lru.Drain()
+	c.storeLen()
+	c.checkCandidate()
+	n := 0
+	for _, ent := range entries {
+		if _, ok := c.pending[ent.Key]; !ok {
+			entries[n] = ent
+			n++
+		}
+		c.forget(ent.Key)
+	}
+	entries = entries[:n]
+	if c.overflow != nil {
+		entries = append(entries, c.overflow.drain()...)
+	}
+	c.lock.Unlock()
+	return entries
+}
+
+// DrainOrdered removes the entries of the cache one by one, from newest to
+// oldest if newestFirst is set and from oldest to newest otherwise, after
+// passing each of them to f, e.g. to flush dirty entries to storage on
+// shutdown. It returns the number of entries processed and removed, and the
+// first error returned by f.
+//
+// An entry for which f fails is kept in the cache. If continueOnError is
+// set, the drain goes on with the next entry, so that the cache is left
+// holding only the failed entries; otherwise it stops at the failed entry,
+// which is left in the cache along with all entries not processed yet.
+// Drained entries are not evictions, so the eviction callback is not
+// invoked for them, and pending entries reserved with AddPending are kept
+// without being passed to f. Entries staged by WithOverflowBuffer are
+// drained as the newest entries.
+//
+// The whole drain runs inside a single critical section, so f must not call
+// methods of the cache, which would deadlock.
+func (c *Cache[Key, Value]) DrainOrdered(newestFirst, continueOnError bool, f func(key Key, value Value) error) (processed int, firstErr error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entries := c.lru.Drain()
+	admitted := len(entries)
+	if c.overflow != nil {
+		entries = append(entries, c.overflow.drain()...)
+	}
+	keep := make([]bool, len(entries))
+	stopped := false
+	for i := range entries {
+		j := i
+		if newestFirst {
+			j = len(entries) - 1 - i
+		}
+		ent := entries[j]
+		if _, ok := c.pending[ent.Key]; ok || stopped {
+			keep[j] = true
+			continue
+		}
+		if err := f(ent.Key, ent.Value); err != nil {
+			keep[j] = true
+			if firstErr == nil {
+				firstErr = err
+			}
+			stopped = !continueOnError
+			continue
+		}
+		if j < admitted {
+			c.forget(ent.Key)
+		}
+		processed++
+	}
+	// restore the kept entries in their original order, staging those that
+	// no longer fit
+	var kept []simplelru.Entry[Key, Value]
+	for i, ent := range entries {
+		if keep[i] {
+			kept = append(kept, ent)
+		}
+	}
+	n := len(kept)
+	if n > c.lru.Cap() {
+		n = c.lru.Cap()
+	}
+	for i := n - 1; i >= 0; i-- {
+		c.lru.AddToBack(kept[i].Key, kept[i].Value)
+	}
+	for _, ent := range kept[n:] {
+		c.overflow.push(ent.Key, ent.Value)
+	}
+	c.storeLen()
+	c.checkCandidate()
+	return processed, firstErr
+}
+
 // Add adds a value to the cache. Returns true if an eviction occurred.
 func (c *Cache[Key, Value]) Add(key Key, value Value) (evicted bool) {
+	c.lock.Lock()
+	return c.addAndUnlock(key, value)
+}
+
+// addAndUnlock implements Add. The caller must hold the lock, which is
+// released before the eviction callback is invoked.
+func (c *Cache[Key, Value]) addAndUnlock(key Key, value Value) (evicted bool) {
 	var k Key
 	var v Value
-	c.lock.Lock()
-	evicted = c.lru.Add(key, value)
-	if c.onEvictedCB != nil && evicted {
+	evicted = c.add(key, value)
+	notify := c.onEvictedCB != nil && len(c.evictedKeys) > 0
+	if notify {
 		k, v = c.evictedKeys[0], c.evictedVals[0]
 		c.evictedKeys, c.evictedVals = c.evictedKeys[:0], c.evictedVals[:0]
 	}
 	c.lock.Unlock()
-	if c.onEvictedCB != nil && evicted {
+	if notify {
 		c.onEvictedCB(k, v)
 	}
+	c.endWarmup()
 	return
 }
 
-// Get looks up a key's value from the cache.
+// Set adds a value to the cache like Add, and reports the previous value and
+// the eviction it caused, all computed under a single lock. A pending entry
+// reserved with AddPending does not count as existing.
+func (c *Cache[Key, Value]) Set(key Key, value Value) (result simplelru.SetResult[Key, Value]) {
+	var k Key
+	var v Value
+	c.lock.Lock()
+	result.Previous, result.Existed = c.peek(key)
+	oldestKey, _, _ := c.lru.GetOldest()
+	result.Evicted = c.add(key, value)
+	if result.Evicted {
+		result.EvictedKey = oldestKey
+	}
+	notify := c.onEvictedCB != nil && len(c.evictedKeys) > 0
+	if notify {
+		k, v = c.evictedKeys[0], c.evictedVals[0]
+		c.evictedKeys, c.evictedVals = c.evictedKeys[:0], c.evictedVals[:0]
+	}
+	c.lock.Unlock()
+	if notify {
+		c.onEvictedCB(k, v)
+	}
+	c.endWarmup()
+	return result
+}
+
+// EvictAndAdd removes the victim from the cache, if present, and adds a
+// value to the cache, all under a single lock, so that callers can choose
+// which entry makes room for a new one. If the victim is not in the cache,
+// the value is added like Add, evicting the oldest entry if needed. A
+// pending entry reserved with AddPending is never removed as a victim.
+// Returns true if an entry was evicted, either the victim or the oldest
+// entry.
+func (c *Cache[Key, Value]) EvictAndAdd(victim Key, newKey Key, newValue Value) (evicted bool) {
+	c.lock.Lock()
+	if _, ok := c.peek(victim); ok {
+		_, evicted = c.removeEntry(victim)
+	}
+	if c.add(newKey, newValue) {
+		evicted = true
+	}
+	c.storeLen()
+	c.checkCandidate()
+	ks, vs := c.takeEvicted()
+	c.lock.Unlock()
+	c.notifyEvicted(ks, vs)
+	c.endWarmup()
+	return evicted
+}
+
+// Get looks up a key's value from the cache. If the key was reserved with
+// AddPending and is not filled yet, Get waits until it is filled or leaves
+// the cache.
 func (c *Cache[Key, Value]) Get(key Key) (value Value, ok bool) {
+	c.endWarmup()
 	c.lock.Lock()
-	value, ok = c.lru.Get(key)
+	return c.getAndUnlock(key)
+}
+
+// getAndUnlock implements Get. The caller must hold the lock, which is
+// released before waiting for a pending entry.
+func (c *Cache[Key, Value]) getAndUnlock(key Key) (value Value, ok bool) {
+	value, ok = c.get(key)
+	p := c.pending[key]
+	if p != nil {
+		p.waiters++
+	}
+	ks, vs := c.takeEvicted()
 	c.lock.Unlock()
+	c.notifyEvicted(ks, vs)
+	if p != nil {
+		<-p.done
+		return p.value, p.ok
+	}
 	return value, ok
 }
 
+// QueryResult holds the outcome of a Query.
+type QueryResult[Key comparable, Value any] struct {
+	// Found maps each key that was in the cache to its value.
+	Found map[Key]Value
+	// Missing lists the keys that were not in the cache, in input order.
+	Missing []Key
+	// HitRatio is the fraction of the queried keys that were found, or 0
+	// if no keys were queried.
+	HitRatio float64
+}
+
+// Query looks up several keys under a single lock. Unless promotion is
+// paused, hits are promoted in input order, so the last key found ends up
+// as the most recently used. Duplicate keys are looked up, and counted
+// towards HitRatio, each time they appear.
+func (c *Cache[Key, Value]) Query(keys []Key) QueryResult[Key, Value] {
+	result := QueryResult[Key, Value]{
+		Found: make(map[Key]Value, len(keys)),
+	}
+	hits := 0
+	c.lock.Lock()
+	for _, key := range keys {
+		if value, ok := c.get(key); ok && c.pending[key] == nil {
+			result.Found[key] = value
+			hits++
+		} else {
+			result.Missing = append(result.Missing, key)
+		}
+	}
+	ks, vs := c.takeEvicted()
+	c.lock.Unlock()
+	c.notifyEvicted(ks, vs)
+	if len(keys) > 0 {
+		result.HitRatio = float64(hits) / float64(len(keys))
+	}
+	return result
+}
+
 // Contains checks if a key is in the cache, without updating the
-// recent-ness or deleting it for being stale.
+// recent-ness or deleting it for being stale. Like Peek, it reports a
+// pending entry reserved with AddPending as missing until it is filled.
 func (c *Cache[Key, Value]) Contains(key Key) bool {
+	c.endWarmup()
 	c.lock.RLock()
-	containKey := c.lru.Contains(key)
+	_, containKey := c.peek(key)
 	c.lock.RUnlock()
 	return containKey
 }
@@ -108,73 +695,131 @@ func (c *Cache[Key, Value]) Contains(key Key) bool {
 // Peek returns the key value (or undefined if not found) without updating
 // the "recently used"-ness of the key.
 func (c *Cache[Key, Value]) Peek(key Key) (value Value, ok bool) {
+	c.endWarmup()
 	c.lock.RLock()
-	value, ok = c.lru.Peek(key)
+	value, ok = c.peek(key)
 	c.lock.RUnlock()
 	return value, ok
 }
 
+// TestFunc reports whether the key is in the cache and, if so, whether its
+// value satisfies the predicate, under a single lock. Like Peek, it is a
+// pure read that does not update the "recently used"-ness of the key. The
+// predicate runs inside the critical section, so it must not call methods
+// of the cache, which would deadlock.
+func (c *Cache[Key, Value]) TestFunc(key Key, predicate func(Value) bool) (present, satisfied bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	value, ok := c.peek(key)
+	if !ok {
+		return false, false
+	}
+	return true, predicate(value)
+}
+
+// peek implements Peek. The caller must hold the lock.
+func (c *Cache[Key, Value]) peek(key Key) (value Value, ok bool) {
+	if _, pending := c.pending[key]; !pending {
+		value, ok = c.lookup(key)
+	}
+	return value, ok
+}
+
 // ContainsOrAdd checks if a key is in the cache without updating the
 // recent-ness or deleting it for being stale, and if not, adds the value.
-// Returns whether found and whether an eviction occurred.
+// Returns whether found and whether an eviction occurred. A pending entry
+// reserved with AddPending is not found, so the value fills it.
 func (c *Cache[Key, Value]) ContainsOrAdd(key Key, value Value) (ok, evicted bool) {
 	var k Key
 	var v Value
 	c.lock.Lock()
-	if c.lru.Contains(key) {
+	if _, ok := c.peek(key); ok {
 		c.lock.Unlock()
 		return true, false
 	}
-	evicted = c.lru.Add(key, value)
-	if c.onEvictedCB != nil && evicted {
+	evicted = c.add(key, value)
+	notify := c.onEvictedCB != nil && len(c.evictedKeys) > 0
+	if notify {
 		k, v = c.evictedKeys[0], c.evictedVals[0]
 		c.evictedKeys, c.evictedVals = c.evictedKeys[:0], c.evictedVals[:0]
 	}
 	c.lock.Unlock()
-	if c.onEvictedCB != nil && evicted {
+	if notify {
 		c.onEvictedCB(k, v)
 	}
+	c.endWarmup()
 	return false, evicted
 }
 
 // PeekOrAdd checks if a key is in the cache without updating the
 // recent-ness or deleting it for being stale, and if not, adds the value.
-// Returns whether found and whether an eviction occurred.
+// Returns whether found and whether an eviction occurred. A pending entry
+// reserved with AddPending is not found, so the value fills it.
 func (c *Cache[Key, Value]) PeekOrAdd(key Key, value Value) (previous Value, ok, evicted bool) {
 	var k Key
 	var v Value
 	c.lock.Lock()
-	previous, ok = c.lru.Peek(key)
+	previous, ok = c.peek(key)
 	if ok {
 		c.lock.Unlock()
 		return previous, true, false
 	}
-	evicted = c.lru.Add(key, value)
-	if c.onEvictedCB != nil && evicted {
+	evicted = c.add(key, value)
+	notify := c.onEvictedCB != nil && len(c.evictedKeys) > 0
+	if notify {
 		k, v = c.evictedKeys[0], c.evictedVals[0]
 		c.evictedKeys, c.evictedVals = c.evictedKeys[:0], c.evictedVals[:0]
 	}
 	c.lock.Unlock()
-	if c.onEvictedCB != nil && evicted {
+	if notify {
 		c.onEvictedCB(k, v)
 	}
+	c.endWarmup()
 	var zeroValue Value
 	return zeroValue, false, evicted
 }
 
 // Remove removes the provided key from the cache.
 func (c *Cache[Key, Value]) Remove(key Key) (present bool) {
+	_, present = c.remove(key)
+	return
+}
+
+// remove removes the provided key from the cache, returning its value if it
+// was contained.
+func (c *Cache[Key, Value]) remove(key Key) (value Value, present bool) {
+	c.lock.Lock()
+	return c.removeAndUnlock(key)
+}
+
+// removeEntry removes the provided key from the cache or the overflow
+// buffer, saving it for the eviction callback. The caller must hold the
+// lock.
+func (c *Cache[Key, Value]) removeEntry(key Key) (value Value, present bool) {
+	if value, present = c.lru.Peek(key); present {
+		c.lru.Remove(key)
+		c.admitStaged()
+		return value, true
+	}
+	return c.removeStaged(key)
+}
+
+// removeAndUnlock implements remove. The caller must hold the lock, which is
+// released before the eviction callback is invoked.
+func (c *Cache[Key, Value]) removeAndUnlock(key Key) (value Value, present bool) {
 	var k Key
 	var v Value
-	c.lock.Lock()
-	present = c.lru.Remove(key)
-	if c.onEvictedCB != nil && present {
+	value, present = c.removeEntry(key)
+	c.storeLen()
+	c.checkCandidate()
+	notify := c.onEvictedCB != nil && len(c.evictedKeys) > 0
+	if notify {
 		k, v = c.evictedKeys[0], c.evictedVals[0]
 		c.evictedKeys, c.evictedVals = c.evictedKeys[:0], c.evictedVals[:0]
 	}
 	c.lock.Unlock()
-	if c.onEvictedCB != nil && present {
-		c.onEvicted(k, v)
+	if notify {
+		c.onEvictedCB(k, v)
 	}
 	return
 }
@@ -183,8 +828,16 @@ func (c *Cache[Key, Value]) Remove(key Key) (present bool) {
 func (c *Cache[Key, Value]) Resize(size int) (evicted int) {
 	var ks []Key
 	var vs []Value
+	if c.warmup != nil {
+		atomic.StoreInt32(&c.warmup.done, 1)
+	}
 	c.lock.Lock()
 	evicted = c.lru.Resize(size)
+	c.admitStaged()
+	atomic.AddUint64(&c.evictions, uint64(evicted))
+	atomic.AddUint64(&c.totalEvictions, uint64(evicted))
+	c.storeLen()
+	c.checkCandidate()
 	if c.onEvictedCB != nil && evicted > 0 {
 		ks, vs = c.evictedKeys, c.evictedVals
 		c.initEvictBuffers()
@@ -204,17 +857,66 @@ func (c *Cache[Key, Value]) RemoveOldest() (key Key, value Value, ok bool) {
 	var v Value
 	c.lock.Lock()
 	key, value, ok = c.lru.RemoveOldest()
-	if c.onEvictedCB != nil && ok {
+	c.admitStaged()
+	c.storeLen()
+	c.checkCandidate()
+	notify := c.onEvictedCB != nil && len(c.evictedKeys) > 0
+	if notify {
 		k, v = c.evictedKeys[0], c.evictedVals[0]
 		c.evictedKeys, c.evictedVals = c.evictedKeys[:0], c.evictedVals[:0]
 	}
 	c.lock.Unlock()
-	if c.onEvictedCB != nil && ok {
+	if notify {
 		c.onEvictedCB(k, v)
 	}
 	return
 }
 
+// EvictionCountdown returns an estimate of the number of new keys that can
+// be added before the key is evicted, e.g. to decide whether to persist an
+// entry now or later. The estimate assumes that the key is not accessed in
+// the meantime, which would reset its countdown, that no other entry is
+// removed, and that promotion is not paused. It accounts for the room left
+// in the overflow buffer of WithOverflowBuffer. The cost is linear in the
+// size of the cache.
+func (c *Cache[Key, Value]) EvictionCountdown(key Key) (adds int, ok bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if adds, ok = c.lru.EvictionCountdown(key); ok && c.overflow != nil {
+		adds += c.overflow.size - c.overflow.order.Len()
+	}
+	return adds, ok
+}
+
+// UpdateAll calls f once for each entry of the cache, from oldest to
+// newest, and replaces the value of the entry with the returned value if
+// keep is true, or removes the entry, firing the eviction callback once the
+// walk is done, if keep is false. Replacing values does not promote or
+// reorder the entries. Pending entries reserved with AddPending and entries
+// staged by WithOverflowBuffer are not visited.
+//
+// The whole walk runs inside a single critical section, so f must not call
+// methods of the cache, which would deadlock.
+func (c *Cache[Key, Value]) UpdateAll(f func(key Key, value Value) (newValue Value, keep bool)) {
+	c.lock.Lock()
+	for _, key := range c.lru.Keys() {
+		if _, pending := c.pending[key]; pending {
+			continue
+		}
+		value, _ := c.lru.Peek(key)
+		if newValue, keep := f(key, value); keep {
+			c.replaceValue(key, newValue)
+		} else {
+			c.removeEntry(key)
+		}
+	}
+	c.storeLen()
+	c.checkCandidate()
+	ks, vs := c.takeEvicted()
+	c.lock.Unlock()
+	c.notifyEvicted(ks, vs)
+}
+
 // GetOldest returns the oldest entry
 func (c *Cache[Key, Value]) GetOldest() (key Key, value Value, ok bool) {
 	c.lock.RLock()
@@ -225,16 +927,91 @@ func (c *Cache[Key, Value]) GetOldest() (key Key, value Value, ok bool) {
 
 // Keys returns a slice of the keys in the cache, from oldest to newest.
 func (c *Cache[Key, Value]) Keys() []Key {
+	c.endWarmup()
 	c.lock.RLock()
 	keys := c.lru.Keys()
 	c.lock.RUnlock()
 	return keys
 }
 
+// Page returns up to limit entries, from the newest to the oldest, starting
+// where cursor points, without updating their recent-ness, as described for
+// simplelru.LRU.Page; a non-positive limit returns all of them. Pass next
+// as the cursor of the following page until next.Done() reports that no
+// older entries follow. Pending entries reserved with AddPending are
+// skipped, so a page may hold fewer than limit entries even if more follow.
+//
+// Pagination is best-effort: the cache may change between pages, so
+// entries promoted or added meanwhile can be skipped or returned again. If
+// the entry cursor points after is removed or evicted, Page returns
+// simplelru.ErrCursorNotFound.
+func (c *Cache[Key, Value]) Page(cursor simplelru.PageCursor[Key], limit int) (entries []simplelru.Entry[Key, Value], next simplelru.PageCursor[Key], err error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	entries, next, err = c.lru.Page(cursor, limit)
+	if len(c.pending) == 0 {
+		return entries, next, err
+	}
+	n := 0
+	for _, ent := range entries {
+		if _, pending := c.pending[ent.Key]; !pending {
+			entries[n] = ent
+			n++
+		}
+	}
+	return entries[:n], next, err
+}
+
 // Len returns the number of items in the cache.
 func (c *Cache[Key, Value]) Len() int {
+	c.endWarmup()
 	c.lock.RLock()
 	length := c.lru.Len()
 	c.lock.RUnlock()
 	return length
 }
+
+// LenApprox returns the number of items in the cache without taking the
+// lock, for callers such as metrics scrapes that do not need an exact count
+// and should not contend with writers. The count is updated at the end of
+// every operation that changes the size of the cache, so it may briefly lag
+// behind an operation in progress, but it is never off by more than the
+// changes made by the operations running concurrently.
+func (c *Cache[Key, Value]) LenApprox() int {
+	return int(atomic.LoadInt64(&c.approxLen))
+}
+
+// storeLen updates the count read by LenApprox, and checks whether the
+// cache became full or stopped being full. The caller must hold the lock.
+func (c *Cache[Key, Value]) storeLen() {
+	atomic.StoreInt64(&c.approxLen, int64(c.lru.Len()))
+	c.checkSaturation()
+}
+
+// TakeEvictionCount returns the number of entries evicted to make room, by
+// Add and the other adding methods or by Resize, since the previous call,
+// and resets the count to zero in the same atomic step. Evictions running
+// concurrently are counted by exactly one call, so summing the results of
+// periodic calls never double-counts or loses an eviction. Entries removed
+// explicitly, e.g. by Remove or Purge, are not counted.
+func (c *Cache[Key, Value]) TakeEvictionCount() uint64 {
+	return atomic.SwapUint64(&c.evictions, 0)
+}
+
+// PausePromotion stops the cache from updating the recent-ness of entries
+// until ResumePromotion is called. While paused, Get behaves like Peek and
+// Add inserts new entries as the oldest ones, so that e.g. a sequential scan
+// does not push the working set out of the cache. This is a global toggle
+// affecting all concurrent callers, and calls do not nest.
+func (c *Cache[Key, Value]) PausePromotion() {
+	c.lock.Lock()
+	c.promotionPaused = true
+	c.lock.Unlock()
+}
+
+// ResumePromotion restores the normal behavior after PausePromotion.
+func (c *Cache[Key, Value]) ResumePromotion() {
+	c.lock.Lock()
+	c.promotionPaused = false
+	c.lock.Unlock()
+}
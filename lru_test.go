@@ -1,8 +1,14 @@
 package lru
 
 import (
+	"errors"
+	"fmt"
 	"math/rand"
+	"sync"
+	"sync/atomic"
 	"testing"
+
+	"github.com/errorhandler/golang-lru/simplelru"
 )
 
 func BenchmarkLRU_Rand(b *testing.B) {
@@ -291,3 +297,573 @@ func TestLRUResize(t *testing.T) {
 		t.Errorf("Cache should have contained 2 elements")
 	}
 }
+
+func TestLRUQuery(t *testing.T) {
+	l, err := New[int, int](4)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		l.Add(i, i*10)
+	}
+
+	res := l.Query([]int{2, 5, 0, 6})
+	if len(res.Found) != 2 || res.Found[2] != 20 || res.Found[0] != 0 {
+		t.Fatalf("bad found: %v", res.Found)
+	}
+	if len(res.Missing) != 2 || res.Missing[0] != 5 || res.Missing[1] != 6 {
+		t.Fatalf("bad missing: %v", res.Missing)
+	}
+	if res.HitRatio != 0.5 {
+		t.Fatalf("bad hit ratio: %v", res.HitRatio)
+	}
+
+	// hits are promoted in input order
+	keys := l.Keys()
+	if keys[2] != 2 || keys[3] != 0 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+
+	res = l.Query(nil)
+	if len(res.Found) != 0 || len(res.Missing) != 0 || res.HitRatio != 0 {
+		t.Fatalf("bad empty query: %v", res)
+	}
+}
+
+func TestLRUPausePromotion(t *testing.T) {
+	l, err := New[int, int](4)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		l.Add(i, i)
+	}
+
+	l.PausePromotion()
+	l.Get(0)
+	if k, _, _ := l.GetOldest(); k != 0 {
+		t.Fatalf("get should not promote while paused")
+	}
+	for i := 10; i < 20; i++ {
+		l.Add(i, i)
+	}
+	// the scanned entries only ever displaced each other and the oldest entry
+	if !l.Contains(1) || !l.Contains(2) || !l.Contains(3) || !l.Contains(19) {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+	l.ResumePromotion()
+
+	l.Get(19)
+	if keys := l.Keys(); keys[len(keys)-1] != 19 {
+		t.Fatalf("get should promote after resume: %v", keys)
+	}
+}
+
+func TestLRUDrain(t *testing.T) {
+	evictCounter := 0
+	l, err := NewWithEvict(4, func(k int, v int) {
+		evictCounter++
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		l.Add(i, i)
+	}
+
+	entries := l.Drain()
+	if len(entries) != 4 || entries[0].Key != 0 || entries[3].Key != 3 {
+		t.Fatalf("bad entries: %v", entries)
+	}
+	if l.Len() != 0 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+	if evictCounter != 0 {
+		t.Fatalf("bad evict count: %v", evictCounter)
+	}
+}
+
+func TestLRURemoveEvict(t *testing.T) {
+	var evicted []int
+	l, err := NewWithEvict(2, func(k int, v int) {
+		evicted = append(evicted, k)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Remove(1)
+	l.Add(3, 3)
+	l.Add(4, 4)
+	if len(evicted) != 2 || evicted[0] != 1 || evicted[1] != 2 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+}
+
+func TestLRUEvictContext(t *testing.T) {
+	// evicting a key below 100 cascades to its child key+100
+	var evicted []int
+	l, err := NewWithEvictContext(6, func(ctx EvictContext[int, int], k int, v int) {
+		evicted = append(evicted, k)
+		if k < 100 {
+			if _, ok := ctx.Peek(k + 100); !ok {
+				return
+			}
+			if !ctx.Remove(k + 100) {
+				t.Fatalf("should remove child")
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		l.Add(i+100, i)
+		l.Add(i, i)
+	}
+
+	l.Add(3, 3) // evicts 100
+	if len(evicted) != 1 || evicted[0] != 100 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	l.Add(4, 4) // evicts 0, cascading to nothing
+	l.Add(5, 5) // evicts 101, then 1 on the next add
+	l.Add(6, 6)
+	if l.Contains(1) || l.Contains(101) {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+
+	l.Remove(2) // cascades to 102
+	if l.Contains(102) {
+		t.Fatalf("remove should cascade")
+	}
+	if evicted[len(evicted)-1] != 102 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	if l.Len() != 4 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+
+	l.Purge()
+	l.Add(7, 7)
+	l.Add(107, 107)
+	for i := 8; i < 12; i++ {
+		l.Add(i, i)
+	}
+	l.Add(12, 12) // evicts 7, cascading to 107
+	if l.Contains(7) || l.Contains(107) {
+		t.Fatalf("eviction should cascade: %v", l.Keys())
+	}
+	if l.Len() != 5 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+}
+
+func TestLRULenApprox(t *testing.T) {
+	l, err := New[int, int](4)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	check := func() {
+		t.Helper()
+		if l.LenApprox() != l.Len() {
+			t.Fatalf("bad approx len: %v != %v", l.LenApprox(), l.Len())
+		}
+	}
+
+	for i := 0; i < 6; i++ {
+		l.Add(i, i)
+		check()
+	}
+	l.ContainsOrAdd(10, 10)
+	check()
+	l.Remove(10)
+	check()
+	l.RemoveOldest()
+	check()
+	l.Resize(1)
+	check()
+	l.PeekOrAdd(11, 11)
+	check()
+	l.Drain()
+	check()
+	l.Add(1, 1)
+	l.Purge()
+	check()
+}
+
+func TestLRUTakeEvictionCount(t *testing.T) {
+	l, err := New[int, int](4)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 6; i++ {
+		l.Add(i, i)
+	}
+	l.Remove(5)
+	if n := l.TakeEvictionCount(); n != 2 {
+		t.Fatalf("bad eviction count: %v", n)
+	}
+	if n := l.TakeEvictionCount(); n != 0 {
+		t.Fatalf("bad eviction count: %v", n)
+	}
+	l.Resize(1)
+	l.Purge()
+	if n := l.TakeEvictionCount(); n != 2 {
+		t.Fatalf("bad eviction count: %v", n)
+	}
+
+	// concurrent takes must neither lose nor double-count evictions
+	var wg sync.WaitGroup
+	var taken uint64
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				atomic.AddUint64(&taken, l.TakeEvictionCount())
+			}
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		l.Add(i, i)
+	}
+	close(done)
+	wg.Wait()
+	taken += l.TakeEvictionCount()
+	if taken != 999 {
+		t.Fatalf("bad eviction count: %v", taken)
+	}
+}
+
+func TestLRUSet(t *testing.T) {
+	var evicted []int
+	l, err := NewWithEvict(2, func(k, v int) {
+		evicted = append(evicted, k)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if r := l.Set(1, 1); r.Existed || r.Evicted {
+		t.Fatalf("bad result: %+v", r)
+	}
+	l.Set(2, 2)
+	if r := l.Set(1, 10); !r.Existed || r.Evicted || r.Previous != 1 {
+		t.Fatalf("bad result: %+v", r)
+	}
+	if r := l.Set(3, 3); r.Existed || !r.Evicted || r.EvictedKey != 2 {
+		t.Fatalf("bad result: %+v", r)
+	}
+	if len(evicted) != 1 || evicted[0] != 2 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+
+	// a pending entry does not count as existing
+	l.AddPending(4)
+	if r := l.Set(4, 4); r.Existed {
+		t.Fatalf("bad result: %+v", r)
+	}
+	if v, ok := l.Get(4); !ok || v != 4 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+}
+
+func TestLRUDrainOrdered(t *testing.T) {
+	evictCounter := 0
+	l, err := NewWithEvict(8, func(k, v int) {
+		evictCounter++
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	fill := func() {
+		l.Purge()
+		for i := 0; i < 5; i++ {
+			l.Add(i, i)
+		}
+		evictCounter = 0
+	}
+
+	for _, newestFirst := range []bool{false, true} {
+		fill()
+		var seen []int
+		n, err := l.DrainOrdered(newestFirst, false, func(k, v int) error {
+			seen = append(seen, k)
+			return nil
+		})
+		if err != nil || n != 5 || l.Len() != 0 || evictCounter != 0 {
+			t.Fatalf("bad drain: %v %v %v %v", n, err, l.Len(), evictCounter)
+		}
+		for i, k := range seen {
+			want := i
+			if newestFirst {
+				want = 4 - i
+			}
+			if k != want {
+				t.Fatalf("bad order: %v", seen)
+			}
+		}
+	}
+
+	// stop at the first error
+	errFail := errors.New("fail")
+	fill()
+	n, err := l.DrainOrdered(true, false, func(k, v int) error {
+		if k == 2 {
+			return errFail
+		}
+		return nil
+	})
+	if err != errFail || n != 2 {
+		t.Fatalf("bad drain: %v %v", n, err)
+	}
+	if keys := l.Keys(); len(keys) != 3 || keys[0] != 0 || keys[2] != 2 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+
+	// continue past errors, keeping the failed entries in order
+	fill()
+	n, err = l.DrainOrdered(false, true, func(k, v int) error {
+		if k%2 == 1 {
+			return fmt.Errorf("fail %d", k)
+		}
+		return nil
+	})
+	if err == nil || err.Error() != "fail 1" || n != 3 {
+		t.Fatalf("bad drain: %v %v", n, err)
+	}
+	if keys := l.Keys(); len(keys) != 2 || keys[0] != 1 || keys[1] != 3 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+}
+
+func TestLRUEvictionCountdown(t *testing.T) {
+	l, err := NewWithOptions[int, int](2, nil, WithOverflowBuffer[int, int](2))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Add(3, 3)
+	adds, ok := l.EvictionCountdown(1)
+	if !ok || adds != 2 {
+		t.Fatalf("bad countdown: %v %v", adds, ok)
+	}
+	for i := 0; i < adds; i++ {
+		if !l.Contains(1) {
+			t.Fatalf("evicted after %d adds, expected %d", i, adds)
+		}
+		l.Add(10+i, i)
+	}
+	if l.Contains(1) {
+		t.Fatalf("should be evicted after %d adds", adds)
+	}
+}
+
+func TestLRUUpdateAll(t *testing.T) {
+	var evicted []int
+	l, err := NewWithEvict(8, func(k, v int) {
+		evicted = append(evicted, k)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 6; i++ {
+		l.Add(i, 10)
+	}
+	l.Get(0)
+
+	// drop the even keys and add the key to the value of the odd ones
+	var seen []int
+	l.UpdateAll(func(k, v int) (int, bool) {
+		seen = append(seen, k)
+		return v + k, k%2 == 1
+	})
+	if len(seen) != 6 || seen[0] != 1 || seen[5] != 0 {
+		t.Fatalf("bad walk: %v", seen)
+	}
+	if len(evicted) != 3 || evicted[0] != 2 || evicted[1] != 4 || evicted[2] != 0 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+
+	// survivors keep their order
+	keys := l.Keys()
+	if len(keys) != 3 || keys[0] != 1 || keys[1] != 3 || keys[2] != 5 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	if v, _ := l.Peek(3); v != 13 {
+		t.Fatalf("bad value: %v", v)
+	}
+}
+
+func TestLRUEvictAndAdd(t *testing.T) {
+	var evicted []int
+	l, err := NewWithEvict(3, func(k, v int) {
+		evicted = append(evicted, k)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		l.Add(i, i)
+	}
+
+	// victim present
+	if !l.EvictAndAdd(1, 3, 3) {
+		t.Fatalf("should evict")
+	}
+	if len(evicted) != 1 || evicted[0] != 1 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	if keys := l.Keys(); len(keys) != 3 || keys[0] != 0 || keys[1] != 2 || keys[2] != 3 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+
+	// victim absent, the oldest entry makes room
+	if !l.EvictAndAdd(1, 4, 4) {
+		t.Fatalf("should evict")
+	}
+	if len(evicted) != 2 || evicted[1] != 0 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+
+	// victim absent and room left
+	l.Remove(2)
+	if l.EvictAndAdd(1, 5, 5) {
+		t.Fatalf("should not evict")
+	}
+	if l.Len() != 3 || !l.Contains(5) {
+		t.Fatalf("bad: %v", l.Keys())
+	}
+}
+
+func TestLRUPage(t *testing.T) {
+	l, err := New[int, int](8)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 1; i <= 5; i++ {
+		l.Add(i, i)
+	}
+	fill, already := l.AddPending(6)
+	if already {
+		t.Fatalf("should not be pending")
+	}
+	defer fill(6)
+
+	var keys []int
+	var cursor simplelru.PageCursor[int]
+	for pages := 0; !cursor.Done(); pages++ {
+		var entries []simplelru.Entry[int, int]
+		entries, cursor, err = l.Page(cursor, 2)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		for _, ent := range entries {
+			keys = append(keys, ent.Key)
+		}
+		if pages > 5 {
+			t.Fatalf("cursor is stuck")
+		}
+	}
+	if len(keys) != 5 || keys[0] != 5 || keys[4] != 1 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+
+	l.Remove(3)
+	if _, _, err := l.Page(simplelru.CursorAfter(3), 2); err != simplelru.ErrCursorNotFound {
+		t.Fatalf("err: %v", err)
+	}
+}
+
+func TestLRUTestFunc(t *testing.T) {
+	l, err := New[int, int](2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, 1)
+	l.Add(2, 2)
+	even := func(v int) bool { return v%2 == 0 }
+
+	if present, satisfied := l.TestFunc(2, even); !present || !satisfied {
+		t.Fatalf("bad: %v %v", present, satisfied)
+	}
+	if present, satisfied := l.TestFunc(1, even); !present || satisfied {
+		t.Fatalf("bad: %v %v", present, satisfied)
+	}
+	if present, satisfied := l.TestFunc(3, even); present || satisfied {
+		t.Fatalf("bad: %v %v", present, satisfied)
+	}
+	l.Add(3, 3)
+	if l.Contains(1) {
+		t.Fatalf("should not update recent-ness")
+	}
+}
+
+func TestLRUEvictContext_Resize(t *testing.T) {
+	// evicting a key below 100 cascades to its child key+100
+	var evicted []int
+	l, err := NewWithEvictContext(6, func(ctx EvictContext[int, int], k int, v int) {
+		evicted = append(evicted, k)
+		if k < 100 {
+			ctx.Remove(k + 100)
+		}
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		l.Add(i, i)
+		l.Add(i+100, i)
+	}
+
+	// evicting 0 cascades to 100, which is enough to fit
+	if n := l.Resize(4); n != 1 {
+		t.Fatalf("bad evicted: %v", n)
+	}
+	if len(evicted) != 2 || evicted[0] != 0 || evicted[1] != 100 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	if l.Len() != 4 || !l.Contains(1) {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+}
+
+func TestLRUEvictAndAdd_Rejected(t *testing.T) {
+	var saturated []bool
+	l, err := NewWithOptions(2, nil,
+		WithSecondChanceAdmission[int, int](100, 0.01, testHash),
+		WithSaturationHook[int, int](func(full bool) {
+			saturated = append(saturated, full)
+		}))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		l.Add(i, i)
+		l.Add(i, i)
+	}
+	if l.LenApprox() != 2 || len(saturated) != 1 {
+		t.Fatalf("bad: %v %v", l.LenApprox(), saturated)
+	}
+
+	// the victim is removed while the new key is only recorded
+	if !l.EvictAndAdd(0, 2, 2) {
+		t.Fatalf("should evict")
+	}
+	if l.Contains(2) || l.Len() != 1 {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+	if l.LenApprox() != 1 {
+		t.Fatalf("bad approx len: %v", l.LenApprox())
+	}
+	if len(saturated) != 2 || saturated[1] {
+		t.Fatalf("bad saturation calls: %v", saturated)
+	}
+}
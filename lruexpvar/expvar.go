@@ -0,0 +1,42 @@
+// Package lruexpvar publishes the stats of a cache with the expvar package,
+// so that they can be read at /debug/vars without any metrics library. It is
+// kept apart from the lru package so that only the programs that use it
+// depend on expvar, which registers its handler on http.DefaultServeMux
+// when imported.
+package lruexpvar
+
+import (
+	"expvar"
+	"fmt"
+
+	lru "github.com/errorhandler/golang-lru"
+)
+
+// WithExpvar publishes the stats of the cache as an expvar.Var named name,
+// whose value is a JSON object holding the len, cap, hits, misses,
+// evictions and hit_ratio of the cache, as returned by Cache.Stats at the
+// time the variable is read.
+//
+// Expvar variables are global to the process and cannot be unpublished, so
+// name must be unique, and the variable keeps the cache reachable for the
+// lifetime of the process. Creating the cache fails if a variable of the
+// same name is already published.
+func WithExpvar[Key comparable, Value any](name string) lru.Option[Key, Value] {
+	return func(c *lru.Cache[Key, Value]) error {
+		if expvar.Get(name) != nil {
+			return fmt.Errorf("expvar %q is already published", name)
+		}
+		expvar.Publish(name, expvar.Func(func() any {
+			s := c.Stats()
+			return map[string]any{
+				"len":       s.Len,
+				"cap":       s.Cap,
+				"hits":      s.Hits,
+				"misses":    s.Misses,
+				"evictions": s.Evictions,
+				"hit_ratio": s.HitRatio(),
+			}
+		}))
+		return nil
+	}
+}
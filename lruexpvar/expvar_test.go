@@ -0,0 +1,57 @@
+package lruexpvar
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	lru "github.com/errorhandler/golang-lru"
+)
+
+// testRuns makes the names published by the tests unique across runs of
+// the same test in one process, e.g. with -count, since expvar variables
+// cannot be unpublished.
+var testRuns uint64
+
+func TestWithExpvar(t *testing.T) {
+	name := fmt.Sprintf("%s-%d", t.Name(), atomic.AddUint64(&testRuns, 1))
+	l, err := lru.NewWithOptions(2, nil, WithExpvar[int, int](name))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Add(3, 3)
+	l.Get(3)
+	l.Get(1)
+
+	var stats struct {
+		Len       int
+		Cap       int
+		Hits      uint64
+		Misses    uint64
+		Evictions uint64
+		HitRatio  float64 `json:"hit_ratio"`
+	}
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &stats); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if stats.Len != 2 || stats.Cap != 2 || stats.Hits != 1 || stats.Misses != 1 || stats.Evictions != 1 || stats.HitRatio != 0.5 {
+		t.Fatalf("bad stats: %+v", stats)
+	}
+
+	// the variable reflects later operations
+	l.Get(2)
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &stats); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if stats.Hits != 2 || stats.Misses != 1 {
+		t.Fatalf("bad stats: %+v", stats)
+	}
+
+	if _, err := lru.NewWithOptions(2, nil, WithExpvar[int, int](name)); err == nil {
+		t.Fatalf("should fail with a duplicate name")
+	}
+}
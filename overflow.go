@@ -0,0 +1,157 @@
+package lru
+
+import (
+	"container/list"
+	"fmt"
+
+	"github.com/errorhandler/golang-lru/simplelru"
+)
+
+// WithOverflowBuffer adds a FIFO overflow buffer of up to size entries in
+// front of the cache, to absorb bursts of new keys without immediately
+// evicting the working set. While the cache is full, new keys are staged in
+// the overflow buffer instead of evicting the least recently used entry.
+// Staged entries are admitted into the cache in the order they were added,
+// as soon as room opens up, e.g. by Remove, RemoveOldest or a Resize to a
+// larger size. Once the buffer is full too, adding a new key admits the
+// oldest staged entry, which evicts the least recently used entry as usual,
+// and stages the new key.
+//
+// Get, Peek, Contains, Remove and the adding methods see the staged entries,
+// so a lookup that misses the cache costs a second lookup in the buffer.
+// Staged entries are not promoted by Get, and are not validated by the
+// check of WithGetValidator. Purge and Drain also cover them, Drain
+// returning them after the entries of the cache, while Len, Keys and the
+// other methods reporting on the contents of the cache only cover the
+// admitted entries. AddPending always reserves its slot in the cache
+// itself.
+func WithOverflowBuffer[Key comparable, Value any](size int) Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		if size <= 0 {
+			return fmt.Errorf("must provide a positive overflow buffer size")
+		}
+		c.overflow = &overflowBuffer[Key, Value]{
+			size:  size,
+			order: list.New(),
+			items: make(map[Key]*list.Element),
+		}
+		return nil
+	}
+}
+
+// overflowBuffer is a FIFO of entries waiting for room in the cache.
+type overflowBuffer[Key comparable, Value any] struct {
+	size  int
+	order *list.List
+	items map[Key]*list.Element
+}
+
+// get returns the value of a staged key.
+func (b *overflowBuffer[Key, Value]) get(key Key) (value Value, ok bool) {
+	if ent, ok := b.items[key]; ok {
+		return ent.Value.(*simplelru.Entry[Key, Value]).Value, true
+	}
+	return
+}
+
+// push stages a key as the newest entry, or updates the value of a staged
+// key in place.
+func (b *overflowBuffer[Key, Value]) push(key Key, value Value) {
+	if ent, ok := b.items[key]; ok {
+		ent.Value.(*simplelru.Entry[Key, Value]).Value = value
+		return
+	}
+	b.items[key] = b.order.PushBack(&simplelru.Entry[Key, Value]{Key: key, Value: value})
+}
+
+// pop unstages the oldest entry.
+func (b *overflowBuffer[Key, Value]) pop() (key Key, value Value, ok bool) {
+	if ent := b.order.Front(); ent != nil {
+		kv := b.order.Remove(ent).(*simplelru.Entry[Key, Value])
+		delete(b.items, kv.Key)
+		return kv.Key, kv.Value, true
+	}
+	return
+}
+
+// remove unstages a key.
+func (b *overflowBuffer[Key, Value]) remove(key Key) (value Value, ok bool) {
+	if ent, ok := b.items[key]; ok {
+		delete(b.items, key)
+		return b.order.Remove(ent).(*simplelru.Entry[Key, Value]).Value, true
+	}
+	return
+}
+
+// drain unstages all entries, returning them from oldest to newest.
+func (b *overflowBuffer[Key, Value]) drain() []simplelru.Entry[Key, Value] {
+	entries := make([]simplelru.Entry[Key, Value], 0, b.order.Len())
+	for ent := b.order.Front(); ent != nil; ent = ent.Next() {
+		entries = append(entries, *ent.Value.(*simplelru.Entry[Key, Value]))
+	}
+	b.order.Init()
+	b.items = make(map[Key]*list.Element)
+	return entries
+}
+
+// stages reports whether a new key must be staged rather than added. The
+// caller must hold the lock.
+func (c *Cache[Key, Value]) stages(key Key) bool {
+	if c.overflow == nil || c.lru.Contains(key) {
+		return false
+	}
+	if _, ok := c.overflow.items[key]; ok {
+		return true
+	}
+	return c.lru.Len() >= c.lru.Cap()
+}
+
+// stage adds a value to the overflow buffer, admitting the oldest staged
+// entry first if the buffer is full. The caller must hold the lock.
+func (c *Cache[Key, Value]) stage(key Key, value Value) (evicted bool) {
+	if _, ok := c.overflow.items[key]; !ok && c.overflow.order.Len() >= c.overflow.size {
+		k, v, _ := c.overflow.pop()
+		evicted = c.addMain(k, v)
+	}
+	c.overflow.push(key, value)
+	return evicted
+}
+
+// admitStaged moves staged entries into the cache while it has room. The
+// caller must hold the lock.
+func (c *Cache[Key, Value]) admitStaged() {
+	if c.overflow == nil {
+		return
+	}
+	for c.overflow.order.Len() > 0 && c.lru.Len() < c.lru.Cap() {
+		k, v, _ := c.overflow.pop()
+		c.addMain(k, v)
+	}
+}
+
+// lookup returns the value of a key in the cache or the overflow buffer,
+// without updating its recent-ness. The caller must hold the lock.
+func (c *Cache[Key, Value]) lookup(key Key) (value Value, ok bool) {
+	if value, ok = c.lru.Peek(key); ok || c.overflow == nil {
+		return value, ok
+	}
+	return c.overflow.get(key)
+}
+
+// removeStaged removes a staged key, saving it for the eviction callback
+// like an entry evicted from the cache. The caller must hold the lock.
+func (c *Cache[Key, Value]) removeStaged(key Key) (value Value, present bool) {
+	if c.overflow == nil {
+		return value, false
+	}
+	if value, present = c.overflow.remove(key); present {
+		c.evictedStaged(key, value)
+	}
+	return value, present
+}
+
+// evictedStaged saves a staged entry leaving the overflow buffer for the
+// eviction callback. Staged entries have no per-key state to forget.
+func (c *Cache[Key, Value]) evictedStaged(k Key, v Value) {
+	c.saveEvicted(k, v)
+}
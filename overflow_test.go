@@ -0,0 +1,117 @@
+package lru
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithOverflowBuffer(t *testing.T) {
+	var evicted []int
+	l, err := NewWithOptions(2, func(k, v int) {
+		evicted = append(evicted, k)
+	}, WithOverflowBuffer[int, int](2))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add(1, 1)
+	l.Add(2, 2)
+
+	// a burst is staged instead of evicting the working set
+	if l.Add(3, 3) || l.Add(4, 4) {
+		t.Fatalf("should not evict")
+	}
+	if l.Len() != 2 || !l.Contains(3) || !l.Contains(4) {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+	if v, ok := l.Get(3); !ok || v != 3 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	if v, ok := l.Peek(4); !ok || v != 4 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	if ok, _ := l.ContainsOrAdd(4, 40); !ok {
+		t.Fatalf("should contain 4")
+	}
+
+	// staged entries are admitted in order as room opens up
+	l.Remove(1)
+	if keys := l.Keys(); len(keys) != 2 || keys[0] != 2 || keys[1] != 3 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	if len(evicted) != 1 || evicted[0] != 1 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+
+	// once the buffer is full, the oldest staged entry is admitted
+	l.Add(5, 5)
+	if l.Add(6, 6) != true {
+		t.Fatalf("should evict")
+	}
+	if keys := l.Keys(); len(keys) != 2 || keys[0] != 3 || keys[1] != 4 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	if len(evicted) != 2 || evicted[1] != 2 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+
+	// removing a staged entry evicts it
+	if !l.Remove(6) || l.Contains(6) {
+		t.Fatalf("should remove 6")
+	}
+	if len(evicted) != 3 || evicted[2] != 6 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+
+	l.Resize(3)
+	if keys := l.Keys(); len(keys) != 3 || keys[2] != 5 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+
+	l.Add(7, 7)
+	entries := l.Drain()
+	if len(entries) != 4 || entries[3].Key != 7 || l.Contains(7) {
+		t.Fatalf("bad entries: %v", entries)
+	}
+
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Add(3, 3)
+	l.Add(4, 4)
+	evicted = nil
+	l.Purge()
+	if len(evicted) != 4 || l.Contains(4) {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+}
+
+func TestWithOverflowBuffer_DrainOrdered(t *testing.T) {
+	l, err := NewWithOptions[int, int](2, nil, WithOverflowBuffer[int, int](2))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		l.Add(i, i)
+	}
+	errFail := errors.New("fail")
+	var seen []int
+	n, err := l.DrainOrdered(false, true, func(k, v int) error {
+		seen = append(seen, k)
+		if k == 0 {
+			return nil
+		}
+		return errFail
+	})
+	if n != 1 || err != errFail || len(seen) != 4 || seen[3] != 3 {
+		t.Fatalf("bad drain: %v %v %v", n, err, seen)
+	}
+	if keys := l.Keys(); len(keys) != 2 || keys[0] != 1 || !l.Contains(3) {
+		t.Fatalf("bad keys: %v", keys)
+	}
+}
+
+func TestWithOverflowBuffer_Invalid(t *testing.T) {
+	if _, err := NewWithOptions[int, int](2, nil, WithOverflowBuffer[int, int](0)); err == nil {
+		t.Fatalf("should fail with invalid size")
+	}
+}
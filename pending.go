@@ -0,0 +1,99 @@
+package lru
+
+import "time"
+
+// pendingValue is the placeholder of an entry reserved by AddPending. done
+// is closed once the entry is filled, or once it leaves the cache unfilled.
+type pendingValue[Value any] struct {
+	done  chan struct{}
+	value Value
+	ok    bool
+	// waiters counts the callers that waited for the entry.
+	waiters int
+}
+
+// AddPending reserves a slot for the key, as the most recently used entry,
+// before its value is known, so that the value can be computed without
+// holding the cache lock. The returned fill function sets the value once it
+// is ready; until then, Get on the key waits for it, while Peek, Contains
+// and Query report it as missing. Adding a value for the key with Add,
+// ContainsOrAdd or PeekOrAdd also fills it.
+//
+// If the key is already in the cache or pending, nothing is reserved,
+// already is true and fill does nothing. If the entry leaves the cache
+// before it is filled, waiting callers are released with a miss and fill
+// does nothing. The eviction callback is not invoked for entries that
+// leave the cache unfilled, as they hold no value.
+func (c *Cache[Key, Value]) AddPending(key Key) (fill func(Value), already bool) {
+	var k Key
+	var v Value
+	c.lock.Lock()
+	if _, ok := c.lookup(key); ok {
+		c.lock.Unlock()
+		return func(Value) {}, true
+	}
+	if c.keyTooLarge(key) || c.zeroKeyRejected(key) {
+		c.lock.Unlock()
+		return func(Value) {}, false
+	}
+	var zeroValue Value
+	// reserve a slot in the cache itself, even while the overflow buffer
+	// of WithOverflowBuffer is in use
+	c.addMain(key, zeroValue)
+	if c.pending == nil {
+		c.pending = make(map[Key]*pendingValue[Value])
+	}
+	p := &pendingValue[Value]{done: make(chan struct{})}
+	c.pending[key] = p
+	notify := c.onEvictedCB != nil && len(c.evictedKeys) > 0
+	if notify {
+		k, v = c.evictedKeys[0], c.evictedVals[0]
+		c.evictedKeys, c.evictedVals = c.evictedKeys[:0], c.evictedVals[:0]
+	}
+	c.lock.Unlock()
+	if notify {
+		c.onEvictedCB(k, v)
+	}
+	return func(value Value) {
+		c.lock.Lock()
+		if c.pending[key] == p {
+			c.replaceValue(key, value)
+			c.resolvePending(key, p, value)
+		}
+		c.lock.Unlock()
+	}, false
+}
+
+// resolvePending completes a pending entry with its value and releases the
+// callers waiting for it. The caller must hold the lock.
+func (c *Cache[Key, Value]) resolvePending(key Key, p *pendingValue[Value], value Value) {
+	delete(c.pending, key)
+	p.value, p.ok = value, true
+	close(p.done)
+}
+
+// GetTimeout is like Get, but waits at most timeout for a pending entry to
+// be filled, returning a miss if it is not filled in time.
+func (c *Cache[Key, Value]) GetTimeout(key Key, timeout time.Duration) (value Value, ok bool) {
+	c.lock.Lock()
+	value, ok = c.get(key)
+	p := c.pending[key]
+	if p != nil {
+		p.waiters++
+	}
+	ks, vs := c.takeEvicted()
+	c.lock.Unlock()
+	c.notifyEvicted(ks, vs)
+	if p == nil {
+		return value, ok
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-p.done:
+		return p.value, p.ok
+	case <-timer.C:
+		var zeroValue Value
+		return zeroValue, false
+	}
+}
@@ -0,0 +1,161 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUAddPending(t *testing.T) {
+	l, err := New[int, int](2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	fill, already := l.AddPending(1)
+	if already {
+		t.Fatalf("should not be present")
+	}
+	if l.Len() != 1 {
+		t.Fatalf("slot should be reserved")
+	}
+	if l.Contains(1) {
+		t.Fatalf("contains should miss while pending")
+	}
+	if _, ok := l.Peek(1); ok {
+		t.Fatalf("peek should miss while pending")
+	}
+	if res := l.Query([]int{1}); len(res.Missing) != 1 {
+		t.Fatalf("query should miss while pending")
+	}
+	if _, ok := l.GetTimeout(1, time.Millisecond); ok {
+		t.Fatalf("should time out while pending")
+	}
+
+	got := make(chan int)
+	go func() {
+		v, ok := l.Get(1)
+		if !ok {
+			v = -1
+		}
+		got <- v
+	}()
+	fill(10)
+	if v := <-got; v != 10 {
+		t.Fatalf("bad value: %v", v)
+	}
+	if v, ok := l.Peek(1); !ok || v != 10 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+
+	// filling again is a no-op
+	fill(20)
+	if v, _ := l.Peek(1); v != 10 {
+		t.Fatalf("bad value: %v", v)
+	}
+
+	if _, already = l.AddPending(1); !already {
+		t.Fatalf("should be present")
+	}
+}
+
+func TestLRUAddPending_Evicted(t *testing.T) {
+	var evicted []int
+	l, err := NewWithEvict(1, func(k int, v int) {
+		evicted = append(evicted, k)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	fill, _ := l.AddPending(1)
+	got := make(chan bool)
+	go func() {
+		_, ok := l.Get(1)
+		got <- ok
+	}()
+	waitForWaiters(t, l, 1, 1)
+	l.Add(2, 2) // evicts the pending entry
+	if ok := <-got; ok {
+		t.Fatalf("should miss once evicted")
+	}
+	fill(10)
+	if _, ok := l.Peek(1); ok || l.Len() != 1 {
+		t.Fatalf("fill should not revive the entry")
+	}
+	if len(evicted) != 0 {
+		t.Fatalf("unfilled entry should not be evicted: %v", evicted)
+	}
+
+	l.Add(3, 3)
+	if len(evicted) != 1 || evicted[0] != 2 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+}
+
+// waitForWaiters waits until n callers wait for the pending entry of key.
+func waitForWaiters(t *testing.T, l *Cache[int, int], key, n int) {
+	deadline := time.Now().Add(time.Second)
+	for {
+		l.lock.Lock()
+		p := l.pending[key]
+		waiting := p != nil && p.waiters >= n
+		l.lock.Unlock()
+		if waiting {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("no caller waits for %v", key)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLRUAddPending_ContainsOrAdd(t *testing.T) {
+	l, err := New[int, int](2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	fill, _ := l.AddPending(1)
+	if ok, _ := l.ContainsOrAdd(1, 5); ok {
+		t.Fatalf("should not contain a pending entry")
+	}
+	if v, ok := l.Peek(1); !ok || v != 5 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	fill(10)
+
+	l.AddPending(2)
+	if v, ok, _ := l.PeekOrAdd(2, 6); ok || v != 0 {
+		t.Fatalf("should not find a pending entry: %v", v)
+	}
+	if v, ok := l.GetTimeout(2, time.Second); !ok || v != 6 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	if l.Len() != 2 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+}
+
+func TestLRUAddPending_Add(t *testing.T) {
+	l, err := New[int, int](2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	fill, _ := l.AddPending(1)
+	l.Add(1, 5)
+	if v, ok := l.GetTimeout(1, time.Second); !ok || v != 5 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	fill(10)
+	if v, _ := l.Peek(1); v != 5 {
+		t.Fatalf("fill should be a no-op after add")
+	}
+
+	l.AddPending(2)
+	if entries := l.Drain(); len(entries) != 1 || entries[0].Key != 1 {
+		t.Fatalf("bad entries: %v", entries)
+	}
+	if len(l.pending) != 0 {
+		t.Fatalf("pending should be dropped")
+	}
+}
@@ -0,0 +1,52 @@
+package lru
+
+// ReadOnlyView is a view of a Cache that only allows reading it, to hand a
+// cache to code that must not modify it. The view shares the underlying
+// cache and its lock, so it reflects the live state of the cache rather
+// than a snapshot.
+type ReadOnlyView[Key comparable, Value any] struct {
+	c *Cache[Key, Value]
+}
+
+// ReadOnly returns a read-only view of the cache.
+func (c *Cache[Key, Value]) ReadOnly() ReadOnlyView[Key, Value] {
+	return ReadOnlyView[Key, Value]{c: c}
+}
+
+// Get looks up a key's value from the cache. Unlike Cache.Get, it does not
+// update the "recently used"-ness of the key.
+func (v ReadOnlyView[Key, Value]) Get(key Key) (value Value, ok bool) {
+	return v.c.Peek(key)
+}
+
+// Contains checks if a key is in the cache.
+func (v ReadOnlyView[Key, Value]) Contains(key Key) bool {
+	return v.c.Contains(key)
+}
+
+// Len returns the number of items in the cache.
+func (v ReadOnlyView[Key, Value]) Len() int {
+	return v.c.Len()
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (v ReadOnlyView[Key, Value]) Keys() []Key {
+	return v.c.Keys()
+}
+
+// Range calls f for each entry of the cache, from oldest to newest, until f
+// returns false. The cache is read locked for the duration of the
+// iteration, so f must not modify the cache.
+func (v ReadOnlyView[Key, Value]) Range(f func(key Key, value Value) bool) {
+	v.c.lock.RLock()
+	defer v.c.lock.RUnlock()
+	for _, key := range v.c.lru.Keys() {
+		if _, pending := v.c.pending[key]; pending {
+			continue
+		}
+		value, _ := v.c.lru.Peek(key)
+		if !f(key, value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package lru
+
+import "testing"
+
+func TestLRUReadOnly(t *testing.T) {
+	l, err := New[int, int](4)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		l.Add(i, i*10)
+	}
+	v := l.ReadOnly()
+
+	if val, ok := v.Get(0); !ok || val != 0 {
+		t.Fatalf("bad: %v %v", val, ok)
+	}
+	if k, _, _ := l.GetOldest(); k != 0 {
+		t.Fatalf("view get should not promote")
+	}
+	if !v.Contains(1) || v.Contains(5) {
+		t.Fatalf("bad contains")
+	}
+
+	// the view reflects live state
+	l.Add(3, 30)
+	if v.Len() != 4 || len(v.Keys()) != 4 {
+		t.Fatalf("bad len: %v", v.Len())
+	}
+
+	var keys []int
+	v.Range(func(k int, val int) bool {
+		if val != k*10 {
+			t.Fatalf("bad value: %v", val)
+		}
+		keys = append(keys, k)
+		return k < 2
+	})
+	if len(keys) != 3 || keys[0] != 0 || keys[2] != 2 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+}
@@ -0,0 +1,33 @@
+package lru
+
+// WithSaturationHook registers a hook that is called with true when the
+// cache becomes full, that is when its length reaches its capacity, and with
+// false when it stops being full after an entry is removed, the cache is
+// purged or drained, or it grows. The hook is edge-triggered: it is called
+// once per transition, not for every operation on a full cache, and never
+// twice in a row with the same value. A cache that is filled to capacity by
+// Add and then emptied calls it exactly twice.
+//
+// The hook runs inside the critical section of the operation that caused the
+// transition, so it must be fast and must not call methods of the cache,
+// which would deadlock.
+func WithSaturationHook[Key comparable, Value any](hook func(full bool)) Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		c.saturationHook = hook
+		return nil
+	}
+}
+
+// checkSaturation calls the saturation hook if the cache became full or
+// stopped being full since the last check. The caller must hold the lock.
+func (c *Cache[Key, Value]) checkSaturation() {
+	if c.saturationHook == nil {
+		return
+	}
+	full := c.lru.Len() >= c.lru.Cap()
+	if full == c.saturated {
+		return
+	}
+	c.saturated = full
+	c.saturationHook(full)
+}
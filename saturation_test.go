@@ -0,0 +1,39 @@
+package lru
+
+import "testing"
+
+func TestWithSaturationHook(t *testing.T) {
+	var calls []bool
+	l, err := NewWithOptions(3, nil, WithSaturationHook[int, int](func(full bool) {
+		calls = append(calls, full)
+	}))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// fill, then keep adding and updating while full
+	for i := 0; i < 6; i++ {
+		l.Add(i, i)
+	}
+	l.Add(5, 50)
+	if len(calls) != 1 || !calls[0] {
+		t.Fatalf("bad calls: %v", calls)
+	}
+
+	// drain
+	l.Remove(3)
+	l.Remove(4)
+	l.Remove(5)
+	if len(calls) != 2 || calls[1] {
+		t.Fatalf("bad calls: %v", calls)
+	}
+
+	// growing a full cache makes it not full
+	for i := 0; i < 3; i++ {
+		l.Add(i, i)
+	}
+	l.Resize(4)
+	if len(calls) != 4 || !calls[2] || calls[3] {
+		t.Fatalf("bad calls: %v", calls)
+	}
+}
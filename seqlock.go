@@ -0,0 +1,233 @@
+package lru
+
+import (
+	"container/list"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// SeqlockLRU is a thread-safe fixed size cache for read-mostly workloads,
+// whose readers never take a lock. Writers serialize on a mutex, while Get,
+// Peek, Contains and Len only use atomic loads, so readers never block each
+// other or wait for a writer.
+//
+// Each entry carries a version counter that a writer makes odd while it
+// replaces or removes the value, and even again once done. A reader loads
+// the version, then the value, then the version again, and retries if the
+// version was odd or changed in between, so that Get returns a value that
+// was current at a single instant and never one that was being removed.
+// Values are published by pointer, so a read is never torn; a retry only
+// happens when a writer updates the same entry concurrently, which is
+// cheap for small values.
+//
+// Readers cannot move entries in the recency list without a lock, so Get
+// only marks an entry as used, and a writer looking for a victim gives the
+// marked entries a second chance, moving them to the front instead of
+// evicting them. Eviction is therefore LRU-approximate, like the CLOCK
+// algorithm. The index from keys to entries is replaced, copy-on-write,
+// whenever a key is added or removed, which costs O(n) per new key, so the
+// cache suits small caches whose set of keys changes rarely compared to
+// how often they are read.
+type SeqlockLRU[Key comparable, Value any] struct {
+	// index holds the current map[Key]*seqEntry, which is never modified
+	// once stored.
+	index atomic.Value
+
+	lock      sync.Mutex
+	size      int
+	evictList *list.List
+}
+
+// seqEntry is an entry of a SeqlockLRU.
+type seqEntry[Key comparable, Value any] struct {
+	// seq is the version of the entry, odd while a writer changes value. It
+	// is accessed atomically and kept first for 64-bit alignment.
+	seq uint64
+
+	// value holds a pointer to the current value, or a nil *Value once the
+	// entry is removed.
+	value atomic.Value
+
+	// used is set by readers and cleared by writers when the entry gets a
+	// second chance. It is accessed atomically.
+	used uint32
+
+	key     Key
+	element *list.Element
+}
+
+// NewSeqlockLRU creates a SeqlockLRU of the given size.
+func NewSeqlockLRU[Key comparable, Value any](size int) (*SeqlockLRU[Key, Value], error) {
+	if size <= 0 {
+		return nil, errors.New("must provide a positive size")
+	}
+	c := &SeqlockLRU[Key, Value]{
+		size:      size,
+		evictList: list.New(),
+	}
+	c.index.Store(make(map[Key]*seqEntry[Key, Value]))
+	return c, nil
+}
+
+// entries returns the current index.
+func (c *SeqlockLRU[Key, Value]) entries() map[Key]*seqEntry[Key, Value] {
+	return c.index.Load().(map[Key]*seqEntry[Key, Value])
+}
+
+// load reads the value of an entry with the optimistic protocol, retrying
+// while a writer changes it.
+func (e *seqEntry[Key, Value]) load() (value Value, ok bool) {
+	for {
+		seq := atomic.LoadUint64(&e.seq)
+		if seq&1 == 1 {
+			runtime.Gosched()
+			continue
+		}
+		p := e.value.Load().(*Value)
+		if atomic.LoadUint64(&e.seq) != seq {
+			continue
+		}
+		if p == nil {
+			return value, false
+		}
+		return *p, true
+	}
+}
+
+// store replaces the value of an entry, or marks it removed if p is nil.
+// The caller must hold the lock.
+func (e *seqEntry[Key, Value]) store(p *Value) {
+	atomic.AddUint64(&e.seq, 1)
+	e.value.Store(p)
+	atomic.AddUint64(&e.seq, 1)
+}
+
+// Get looks up a key's value from the cache, marking it as used.
+func (c *SeqlockLRU[Key, Value]) Get(key Key) (value Value, ok bool) {
+	e, ok := c.entries()[key]
+	if !ok {
+		return value, false
+	}
+	if value, ok = e.load(); ok && atomic.LoadUint32(&e.used) == 0 {
+		atomic.StoreUint32(&e.used, 1)
+	}
+	return value, ok
+}
+
+// Peek returns the key value (or undefined if not found) without marking
+// the key as used.
+func (c *SeqlockLRU[Key, Value]) Peek(key Key) (value Value, ok bool) {
+	e, ok := c.entries()[key]
+	if !ok {
+		return value, false
+	}
+	return e.load()
+}
+
+// Contains checks if a key is in the cache, without marking it as used.
+func (c *SeqlockLRU[Key, Value]) Contains(key Key) bool {
+	_, ok := c.entries()[key]
+	return ok
+}
+
+// Len returns the number of items in the cache.
+func (c *SeqlockLRU[Key, Value]) Len() int {
+	return len(c.entries())
+}
+
+// Add adds a value to the cache as its newest entry. Returns true if an
+// eviction occurred.
+func (c *SeqlockLRU[Key, Value]) Add(key Key, value Value) (evicted bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	old := c.entries()
+	if e, ok := old[key]; ok {
+		e.store(&value)
+		atomic.StoreUint32(&e.used, 0)
+		c.evictList.MoveToFront(e.element)
+		return false
+	}
+
+	var victim *seqEntry[Key, Value]
+	if c.evictList.Len() >= c.size {
+		victim = c.victim()
+		evicted = true
+	}
+	index := make(map[Key]*seqEntry[Key, Value], len(old)+1)
+	for k, e := range old {
+		if e != victim {
+			index[k] = e
+		}
+	}
+	e := &seqEntry[Key, Value]{key: key}
+	e.value.Store(&value)
+	e.element = c.evictList.PushFront(e)
+	index[key] = e
+	c.index.Store(index)
+	return evicted
+}
+
+// victim removes the entry to evict from the recency list and marks it
+// removed, giving used entries a second chance. The caller must hold the
+// lock and update the index.
+func (c *SeqlockLRU[Key, Value]) victim() *seqEntry[Key, Value] {
+	for {
+		e := c.evictList.Back().Value.(*seqEntry[Key, Value])
+		if atomic.LoadUint32(&e.used) == 1 {
+			atomic.StoreUint32(&e.used, 0)
+			c.evictList.MoveToFront(e.element)
+			continue
+		}
+		c.evictList.Remove(e.element)
+		e.store(nil)
+		return e
+	}
+}
+
+// Remove removes the provided key from the cache, returning if the key was
+// contained.
+func (c *SeqlockLRU[Key, Value]) Remove(key Key) (present bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	old := c.entries()
+	e, ok := old[key]
+	if !ok {
+		return false
+	}
+	c.evictList.Remove(e.element)
+	e.store(nil)
+	index := make(map[Key]*seqEntry[Key, Value], len(old)-1)
+	for k, e := range old {
+		if k != key {
+			index[k] = e
+		}
+	}
+	c.index.Store(index)
+	return true
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest,
+// ignoring the second chances that entries used since they were added
+// would get.
+func (c *SeqlockLRU[Key, Value]) Keys() []Key {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	keys := make([]Key, 0, c.evictList.Len())
+	for ent := c.evictList.Back(); ent != nil; ent = ent.Prev() {
+		keys = append(keys, ent.Value.(*seqEntry[Key, Value]).key)
+	}
+	return keys
+}
+
+// Purge is used to completely clear the cache.
+func (c *SeqlockLRU[Key, Value]) Purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for ent := c.evictList.Front(); ent != nil; ent = ent.Next() {
+		ent.Value.(*seqEntry[Key, Value]).store(nil)
+	}
+	c.evictList.Init()
+	c.index.Store(make(map[Key]*seqEntry[Key, Value]))
+}
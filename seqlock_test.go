@@ -0,0 +1,87 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSeqlockLRU(t *testing.T) {
+	if _, err := NewSeqlockLRU[int, int](0); err == nil {
+		t.Fatalf("should fail with zero size")
+	}
+	l, err := NewSeqlockLRU[int, int](3)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if l.Add(i, i) {
+			t.Fatalf("should not evict")
+		}
+	}
+	l.Add(1, 10)
+	if v, ok := l.Peek(1); !ok || v != 10 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+
+	// 0 gets a second chance, so 2 is evicted
+	l.Get(0)
+	if !l.Add(3, 3) {
+		t.Fatalf("should evict")
+	}
+	if l.Contains(2) || !l.Contains(0) || l.Len() != 3 {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+	if keys := l.Keys(); len(keys) != 3 || keys[0] != 1 || keys[1] != 0 || keys[2] != 3 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+
+	if !l.Remove(1) || l.Remove(1) {
+		t.Fatalf("bad remove")
+	}
+	if _, ok := l.Get(1); ok {
+		t.Fatalf("should be removed")
+	}
+	l.Purge()
+	if l.Len() != 0 || len(l.Keys()) != 0 {
+		t.Fatalf("should be purged")
+	}
+}
+
+func TestSeqlockLRU_Concurrent(t *testing.T) {
+	type pair struct{ a, b int }
+	l, err := NewSeqlockLRU[int, pair](16)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < 2; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				k := (i + w) % 32
+				if i%7 == 0 {
+					l.Remove(k)
+				} else {
+					l.Add(k, pair{i, -i})
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 5000; i++ {
+				if v, ok := l.Get(i % 32); ok && v.a != -v.b {
+					t.Errorf("torn read: %v", v)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if l.Len() > 16 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+}
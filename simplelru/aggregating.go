@@ -0,0 +1,111 @@
+package simplelru
+
+import "errors"
+
+// AggregatingLRU is a non-thread safe fixed size LRU cache that maintains a
+// running aggregate over all of its values, such as a sum or a count, so
+// that it can be read without scanning the cache.
+//
+// The aggregate starts as the zero value and is updated by combine each time
+// a value enters the cache, with added set to true, and each time a value
+// leaves it, with added set to false, including when Add overwrites an
+// existing value. The aggregate operation must therefore be invertible:
+// combine(combine(acc, v, true), v, false) must equal acc. Sums and counts
+// qualify, while min and max do not, since removing the current minimum
+// cannot be undone without the other values.
+type AggregatingLRU[Key comparable, Value any] struct {
+	lru       *LRU[Key, Value]
+	combine   func(acc, v Value, added bool) Value
+	aggregate Value
+}
+
+// NewAggregatingLRU constructs an AggregatingLRU of the given size.
+func NewAggregatingLRU[Key comparable, Value any](size int, combine func(acc, v Value, added bool) Value) (*AggregatingLRU[Key, Value], error) {
+	if combine == nil {
+		return nil, errors.New("must provide a combine function")
+	}
+	c := &AggregatingLRU[Key, Value]{
+		combine: combine,
+	}
+	lru, err := NewLRU(size, c.evicted)
+	if err != nil {
+		return nil, err
+	}
+	c.lru = lru
+	return c, nil
+}
+
+// evicted removes the contribution of a value leaving the cache.
+func (c *AggregatingLRU[Key, Value]) evicted(key Key, value Value) {
+	c.aggregate = c.combine(c.aggregate, value, false)
+}
+
+// Aggregate returns the current aggregate over all values in the cache.
+func (c *AggregatingLRU[Key, Value]) Aggregate() Value {
+	return c.aggregate
+}
+
+// Add adds a value to the cache. Returns true if an eviction occurred.
+func (c *AggregatingLRU[Key, Value]) Add(key Key, value Value) (evicted bool) {
+	if old, ok := c.lru.Peek(key); ok {
+		c.aggregate = c.combine(c.aggregate, old, false)
+	}
+	c.aggregate = c.combine(c.aggregate, value, true)
+	return c.lru.Add(key, value)
+}
+
+// Get looks up a key's value from the cache.
+func (c *AggregatingLRU[Key, Value]) Get(key Key) (value Value, ok bool) {
+	return c.lru.Get(key)
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (c *AggregatingLRU[Key, Value]) Contains(key Key) (ok bool) {
+	return c.lru.Contains(key)
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *AggregatingLRU[Key, Value]) Peek(key Key) (value Value, ok bool) {
+	return c.lru.Peek(key)
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *AggregatingLRU[Key, Value]) Remove(key Key) (present bool) {
+	return c.lru.Remove(key)
+}
+
+// RemoveOldest removes the oldest item from the cache.
+func (c *AggregatingLRU[Key, Value]) RemoveOldest() (key Key, value Value, ok bool) {
+	return c.lru.RemoveOldest()
+}
+
+// GetOldest returns the oldest entry
+func (c *AggregatingLRU[Key, Value]) GetOldest() (key Key, value Value, ok bool) {
+	return c.lru.GetOldest()
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *AggregatingLRU[Key, Value]) Keys() []Key {
+	return c.lru.Keys()
+}
+
+// Len returns the number of items in the cache.
+func (c *AggregatingLRU[Key, Value]) Len() int {
+	return c.lru.Len()
+}
+
+// Purge is used to completely clear the cache, resetting the aggregate to
+// the zero value.
+func (c *AggregatingLRU[Key, Value]) Purge() {
+	c.lru.Purge()
+	var zeroValue Value
+	c.aggregate = zeroValue
+}
+
+// Resize changes the cache size.
+func (c *AggregatingLRU[Key, Value]) Resize(size int) (evicted int) {
+	return c.lru.Resize(size)
+}
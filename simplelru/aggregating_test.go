@@ -0,0 +1,61 @@
+package simplelru
+
+import "testing"
+
+func sumCombine(acc, v int, added bool) int {
+	if added {
+		return acc + v
+	}
+	return acc - v
+}
+
+func TestAggregatingLRU(t *testing.T) {
+	l, err := NewAggregatingLRU[int, int](3, sumCombine)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	check := func(want int) {
+		t.Helper()
+		sum := 0
+		for _, k := range l.Keys() {
+			v, _ := l.Peek(k)
+			sum += v
+		}
+		if sum != want || l.Aggregate() != want {
+			t.Fatalf("bad aggregate: %v, scan %v, want %v", l.Aggregate(), sum, want)
+		}
+	}
+
+	l.Add(1, 10)
+	l.Add(2, 20)
+	l.Add(3, 30)
+	check(60)
+
+	// overwrite
+	l.Add(2, 25)
+	check(65)
+
+	// eviction of 1
+	l.Add(4, 40)
+	check(95)
+
+	l.Remove(3)
+	check(65)
+	l.RemoveOldest()
+	check(40)
+	l.Add(5, 50)
+	l.Resize(1)
+	check(50)
+	l.Purge()
+	check(0)
+}
+
+func TestAggregatingLRU_Invalid(t *testing.T) {
+	if _, err := NewAggregatingLRU[int, int](1, nil); err == nil {
+		t.Fatalf("should fail without combine")
+	}
+	if _, err := NewAggregatingLRU[int, int](0, sumCombine); err == nil {
+		t.Fatalf("should fail with invalid size")
+	}
+}
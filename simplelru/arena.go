@@ -0,0 +1,231 @@
+package simplelru
+
+import "errors"
+
+// ArenaLRU is a non-thread safe fixed size LRU cache with the same behavior
+// as LRU, but whose entries live in a single pre-allocated array of nodes
+// linked by index rather than in individually allocated list elements.
+// Adding an entry reuses a free node instead of allocating, and walking the
+// recency list touches one contiguous array instead of chasing pointers,
+// which reduces allocations and garbage collection work and improves cache
+// locality for large, high-throughput caches.
+//
+// The array is sized for the capacity of the cache upfront, so an ArenaLRU
+// holds the memory for size entries even while mostly empty, and Resize
+// reallocates it. The LRU remains the better choice for caches that are
+// rarely full or often resized. Compare the two with the benchmarks in
+// arena_test.go.
+type ArenaLRU[Key comparable, Value any] struct {
+	size int
+	// nodes[0] is the sentinel of the circular recency list: nodes[0].next
+	// is the newest entry and nodes[0].prev the oldest one.
+	nodes []arenaNode[Key, Value]
+	// free is the index of the first free node, linked through next, or 0
+	// if there are none.
+	free    int
+	items   map[Key]int
+	onEvict EvictCallback[Key, Value]
+}
+
+// arenaNode is used to hold an entry in the nodes array
+type arenaNode[Key, Value any] struct {
+	key        Key
+	value      Value
+	prev, next int
+}
+
+// NewArenaLRU constructs an ArenaLRU of the given size
+func NewArenaLRU[Key comparable, Value any](size int, onEvict EvictCallback[Key, Value]) (*ArenaLRU[Key, Value], error) {
+	if size <= 0 {
+		return nil, errors.New("must provide a positive size")
+	}
+	c := &ArenaLRU[Key, Value]{
+		onEvict: onEvict,
+	}
+	c.init(size)
+	return c, nil
+}
+
+// init allocates empty nodes and items for the given size.
+func (c *ArenaLRU[Key, Value]) init(size int) {
+	c.size = size
+	if size < 0 {
+		size = 0
+	}
+	c.nodes = make([]arenaNode[Key, Value], size+1)
+	for i := 1; i < size; i++ {
+		c.nodes[i].next = i + 1
+	}
+	c.free = 0
+	if size > 0 {
+		c.free = 1
+	}
+	c.items = make(map[Key]int, size)
+}
+
+// Purge is used to completely clear the cache.
+func (c *ArenaLRU[Key, Value]) Purge() {
+	for i := c.nodes[0].prev; i != 0; i = c.nodes[0].prev {
+		c.removeNode(i)
+	}
+}
+
+// Add adds a value to the cache.  Returns true if an eviction occurred.
+func (c *ArenaLRU[Key, Value]) Add(key Key, value Value) (evicted bool) {
+	// Check for existing item
+	if i, ok := c.items[key]; ok {
+		c.moveToFront(i)
+		c.nodes[i].value = value
+		return false
+	}
+
+	// Make room first, since there is no node for the new item otherwise
+	evict := len(c.items) >= c.size
+	if evict {
+		c.removeOldest()
+	}
+
+	// A cache resized to nothing evicts the new item right away
+	i := c.free
+	if i == 0 {
+		if c.onEvict != nil {
+			c.onEvict(key, value)
+		}
+		return true
+	}
+	c.free = c.nodes[i].next
+	c.nodes[i].key, c.nodes[i].value = key, value
+	c.pushFront(i)
+	c.items[key] = i
+	return evict
+}
+
+// Get looks up a key's value from the cache.
+func (c *ArenaLRU[Key, Value]) Get(key Key) (value Value, ok bool) {
+	if i, ok := c.items[key]; ok {
+		c.moveToFront(i)
+		return c.nodes[i].value, true
+	}
+	return
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (c *ArenaLRU[Key, Value]) Contains(key Key) (ok bool) {
+	_, ok = c.items[key]
+	return ok
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *ArenaLRU[Key, Value]) Peek(key Key) (value Value, ok bool) {
+	if i, ok := c.items[key]; ok {
+		return c.nodes[i].value, true
+	}
+	return
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *ArenaLRU[Key, Value]) Remove(key Key) (present bool) {
+	if i, ok := c.items[key]; ok {
+		c.removeNode(i)
+		return true
+	}
+	return false
+}
+
+// RemoveOldest removes the oldest item from the cache.
+func (c *ArenaLRU[Key, Value]) RemoveOldest() (key Key, value Value, ok bool) {
+	if i := c.nodes[0].prev; i != 0 {
+		key, value = c.nodes[i].key, c.nodes[i].value
+		c.removeNode(i)
+		return key, value, true
+	}
+	return
+}
+
+// GetOldest returns the oldest entry
+func (c *ArenaLRU[Key, Value]) GetOldest() (key Key, value Value, ok bool) {
+	if i := c.nodes[0].prev; i != 0 {
+		return c.nodes[i].key, c.nodes[i].value, true
+	}
+	return
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *ArenaLRU[Key, Value]) Keys() []Key {
+	keys := make([]Key, 0, len(c.items))
+	for i := c.nodes[0].prev; i != 0; i = c.nodes[i].prev {
+		keys = append(keys, c.nodes[i].key)
+	}
+	return keys
+}
+
+// Len returns the number of items in the cache.
+func (c *ArenaLRU[Key, Value]) Len() int {
+	return len(c.items)
+}
+
+// Resize changes the cache size, reallocating the nodes array.
+func (c *ArenaLRU[Key, Value]) Resize(size int) (evicted int) {
+	diff := c.Len() - size
+	if diff < 0 {
+		diff = 0
+	}
+	for i := 0; i < diff; i++ {
+		c.removeOldest()
+	}
+	old := c.nodes
+	c.init(size)
+	for i := old[0].prev; i != 0; i = old[i].prev {
+		c.Add(old[i].key, old[i].value)
+	}
+	return diff
+}
+
+// removeOldest removes the oldest item from the cache.
+func (c *ArenaLRU[Key, Value]) removeOldest() {
+	if i := c.nodes[0].prev; i != 0 {
+		c.removeNode(i)
+	}
+}
+
+// removeNode is used to remove a given node from the cache, returning it to
+// the free list.
+func (c *ArenaLRU[Key, Value]) removeNode(i int) {
+	c.unlink(i)
+	n := &c.nodes[i]
+	key, value := n.key, n.value
+	delete(c.items, key)
+	// clear the node so that it does not keep the entry alive
+	*n = arenaNode[Key, Value]{next: c.free}
+	c.free = i
+	if c.onEvict != nil {
+		c.onEvict(key, value)
+	}
+}
+
+// pushFront links node i as the newest entry.
+func (c *ArenaLRU[Key, Value]) pushFront(i int) {
+	head := c.nodes[0].next
+	c.nodes[i].prev, c.nodes[i].next = 0, head
+	c.nodes[head].prev = i
+	c.nodes[0].next = i
+}
+
+// unlink removes node i from the recency list.
+func (c *ArenaLRU[Key, Value]) unlink(i int) {
+	prev, next := c.nodes[i].prev, c.nodes[i].next
+	c.nodes[prev].next = next
+	c.nodes[next].prev = prev
+}
+
+// moveToFront makes node i the newest entry.
+func (c *ArenaLRU[Key, Value]) moveToFront(i int) {
+	if c.nodes[0].next == i {
+		return
+	}
+	c.unlink(i)
+	c.pushFront(i)
+}
@@ -0,0 +1,182 @@
+package simplelru
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func BenchmarkArenaLRU_Rand(b *testing.B) {
+	l, err := NewArenaLRU[int64, int64](8192, nil)
+	if err != nil {
+		b.Fatalf("err: %v", err)
+	}
+	benchmarkRand(b, l)
+}
+
+func BenchmarkArenaLRU_Freq(b *testing.B) {
+	l, err := NewArenaLRU[int64, int64](8192, nil)
+	if err != nil {
+		b.Fatalf("err: %v", err)
+	}
+	benchmarkFreq(b, l)
+}
+
+func BenchmarkLRU_Rand(b *testing.B) {
+	l, err := NewLRU[int64, int64](8192, nil)
+	if err != nil {
+		b.Fatalf("err: %v", err)
+	}
+	benchmarkRand(b, l)
+}
+
+func BenchmarkLRU_Freq(b *testing.B) {
+	l, err := NewLRU[int64, int64](8192, nil)
+	if err != nil {
+		b.Fatalf("err: %v", err)
+	}
+	benchmarkFreq(b, l)
+}
+
+func benchmarkRand(b *testing.B, l LRUCache[int64, int64]) {
+	trace := make([]int64, b.N*2)
+	for i := 0; i < b.N*2; i++ {
+		trace[i] = rand.Int63() % 32768
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var hit, miss int
+	for i := 0; i < 2*b.N; i++ {
+		if i%2 == 0 {
+			l.Add(trace[i], trace[i])
+		} else {
+			_, ok := l.Get(trace[i])
+			if ok {
+				hit++
+			} else {
+				miss++
+			}
+		}
+	}
+	b.Logf("hit: %d miss: %d ratio: %f", hit, miss, float64(hit)/float64(miss))
+}
+
+func benchmarkFreq(b *testing.B, l LRUCache[int64, int64]) {
+	trace := make([]int64, b.N*2)
+	for i := 0; i < b.N*2; i++ {
+		if i%2 == 0 {
+			trace[i] = rand.Int63() % 16384
+		} else {
+			trace[i] = rand.Int63() % 32768
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		l.Add(trace[i], trace[i])
+	}
+	var hit, miss int
+	for i := 0; i < b.N; i++ {
+		_, ok := l.Get(trace[i])
+		if ok {
+			hit++
+		} else {
+			miss++
+		}
+	}
+	b.Logf("hit: %d miss: %d ratio: %f", hit, miss, float64(hit)/float64(miss))
+}
+
+// TestArenaLRU_Model checks the ArenaLRU against the LRU on random
+// operations.
+func TestArenaLRU_Model(t *testing.T) {
+	var arenaEvicted, lruEvicted []int
+	a, err := NewArenaLRU(16, func(k, v int) { arenaEvicted = append(arenaEvicted, k) })
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l, err := NewLRU(16, func(k, v int) { lruEvicted = append(lruEvicted, k) })
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		k := r.Intn(32)
+		switch op := r.Intn(100); {
+		case op < 50:
+			if a.Add(k, i) != l.Add(k, i) {
+				t.Fatalf("bad evicted for add %v", k)
+			}
+		case op < 80:
+			av, aok := a.Get(k)
+			lv, lok := l.Get(k)
+			if av != lv || aok != lok {
+				t.Fatalf("bad get %v: %v %v != %v %v", k, av, aok, lv, lok)
+			}
+		case op < 90:
+			if a.Remove(k) != l.Remove(k) {
+				t.Fatalf("bad remove %v", k)
+			}
+		case op < 95:
+			ak, _, aok := a.RemoveOldest()
+			lk, _, lok := l.RemoveOldest()
+			if ak != lk || aok != lok {
+				t.Fatalf("bad remove oldest: %v %v != %v %v", ak, aok, lk, lok)
+			}
+		case op < 99:
+			size := 1 + r.Intn(24)
+			if a.Resize(size) != l.Resize(size) {
+				t.Fatalf("bad resize %v", size)
+			}
+		default:
+			a.Purge()
+			l.Purge()
+		}
+
+		ak, lk := a.Keys(), l.Keys()
+		if len(ak) != len(lk) || a.Len() != l.Len() {
+			t.Fatalf("bad keys: %v != %v", ak, lk)
+		}
+		for j := range ak {
+			if ak[j] != lk[j] {
+				t.Fatalf("bad keys: %v != %v", ak, lk)
+			}
+		}
+	}
+	if len(arenaEvicted) != len(lruEvicted) {
+		t.Fatalf("bad evicted: %v != %v", len(arenaEvicted), len(lruEvicted))
+	}
+}
+
+func TestArenaLRU(t *testing.T) {
+	l, err := NewArenaLRU[int, int](2, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, 1)
+	l.Add(2, 2)
+	if k, v, ok := l.GetOldest(); !ok || k != 1 || v != 1 {
+		t.Fatalf("bad oldest: %v %v %v", k, v, ok)
+	}
+	l.Get(1)
+	if !l.Add(3, 3) || l.Contains(2) {
+		t.Fatalf("2 should be evicted")
+	}
+	if v, ok := l.Peek(1); !ok || v != 1 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+
+	// a cache resized to nothing holds nothing
+	l.Resize(0)
+	if !l.Add(4, 4) || l.Len() != 0 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+
+	if _, err := NewArenaLRU[int, int](0, nil); err == nil {
+		t.Fatalf("should fail with invalid size")
+	}
+}
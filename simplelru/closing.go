@@ -0,0 +1,86 @@
+package simplelru
+
+import "io"
+
+// ClosingLRU is an LRU whose values are closed whenever they leave the
+// cache, be it through eviction, Remove, RemoveOldest, Resize or Purge, or
+// when Add, Set or AddToBack replaces them with a different value.
+type ClosingLRU[Key comparable, Value io.Closer] struct {
+	*LRU[Key, Value]
+	onEvict    EvictCallback[Key, Value]
+	onCloseErr func(key Key, err error)
+}
+
+// NewClosingLRU constructs a ClosingLRU of the given size.
+//
+// If onEvict is not nil, it is invoked first, while the value is still
+// open, and the value is closed afterwards; this includes values replaced
+// by a different one. Errors returned by Close are passed to onCloseErr if
+// it is not nil and are discarded otherwise.
+func NewClosingLRU[Key comparable, Value io.Closer](size int, onEvict EvictCallback[Key, Value], onCloseErr func(key Key, err error)) (*ClosingLRU[Key, Value], error) {
+	c := &ClosingLRU[Key, Value]{onEvict: onEvict, onCloseErr: onCloseErr}
+	lru, err := NewLRU(size, c.close)
+	if err != nil {
+		return nil, err
+	}
+	c.LRU = lru
+	return c, nil
+}
+
+// Add adds a value to the cache, closing the value it replaces unless it
+// is the same one. Returns true if an eviction occurred.
+func (c *ClosingLRU[Key, Value]) Add(key Key, value Value) (evicted bool) {
+	return c.Set(key, value).Evicted
+}
+
+// Set adds a value to the cache like Add, and reports the previous value,
+// which is closed unless it is the same as value, and the eviction it
+// caused.
+func (c *ClosingLRU[Key, Value]) Set(key Key, value Value) (result SetResult[Key, Value]) {
+	result = c.LRU.Set(key, value)
+	if result.Existed {
+		c.replaced(key, result.Previous, value)
+	}
+	return result
+}
+
+// AddToBack adds a value to the cache as the oldest entry like
+// LRU.AddToBack, closing the value it replaces unless it is the same one.
+// Returns true if an eviction occurred.
+func (c *ClosingLRU[Key, Value]) AddToBack(key Key, value Value) (evicted bool) {
+	previous, existed := c.LRU.Peek(key)
+	evicted = c.LRU.AddToBack(key, value)
+	if existed {
+		c.replaced(key, previous, value)
+	}
+	return evicted
+}
+
+// replaced closes the previous value of key unless it is the same as value.
+func (c *ClosingLRU[Key, Value]) replaced(key Key, previous, value Value) {
+	if !sameValue(previous, value) {
+		c.close(key, previous)
+	}
+}
+
+// close invokes the eviction callback, then closes the value.
+func (c *ClosingLRU[Key, Value]) close(key Key, value Value) {
+	if c.onEvict != nil {
+		c.onEvict(key, value)
+	}
+	if err := value.Close(); err != nil && c.onCloseErr != nil {
+		c.onCloseErr(key, err)
+	}
+}
+
+// sameValue reports whether a and b are equal. Values of a type that
+// cannot be compared, which would make the comparison panic, are never the
+// same.
+func sameValue[Value any](a, b Value) (same bool) {
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+	return any(a) == any(b)
+}
@@ -0,0 +1,113 @@
+package simplelru
+
+import (
+	"errors"
+	"testing"
+)
+
+type testCloser struct {
+	closed int
+	err    error
+}
+
+func (c *testCloser) Close() error {
+	c.closed++
+	return c.err
+}
+
+func TestClosingLRU(t *testing.T) {
+	var evicted []int
+	var closeErrs []int
+	l, err := NewClosingLRU(2, func(k int, v *testCloser) {
+		if v.closed != 0 {
+			t.Fatalf("should be evicted before close")
+		}
+		evicted = append(evicted, k)
+	}, func(k int, err error) {
+		closeErrs = append(closeErrs, k)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	closers := make([]*testCloser, 5)
+	for i := range closers {
+		closers[i] = &testCloser{}
+	}
+	closers[2].err = errors.New("close failed")
+
+	l.Add(0, closers[0])
+	l.Add(1, closers[1])
+	l.Add(2, closers[2]) // evicts 0
+	l.Remove(1)
+	l.Add(3, closers[3])
+	l.Resize(1) // evicts 2
+	l.Add(4, closers[4])
+	l.Purge()
+
+	for i, c := range closers {
+		if c.closed != 1 {
+			t.Fatalf("closer %d closed %d times", i, c.closed)
+		}
+	}
+	if len(evicted) != 5 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	if len(closeErrs) != 1 || closeErrs[0] != 2 {
+		t.Fatalf("bad close errors: %v", closeErrs)
+	}
+}
+
+func TestClosingLRU_NoCallbacks(t *testing.T) {
+	l, err := NewClosingLRU[int, *testCloser](1, nil, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	c := &testCloser{err: errors.New("close failed")}
+	l.Add(1, c)
+	l.Add(2, &testCloser{})
+	if c.closed != 1 {
+		t.Fatalf("should be closed")
+	}
+}
+
+func TestClosingLRU_Replace(t *testing.T) {
+	var evicted []int
+	l, err := NewClosingLRU(2, func(k int, v *testCloser) {
+		if v.closed != 0 {
+			t.Fatalf("should be evicted before close")
+		}
+		evicted = append(evicted, k)
+	}, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	old, same := &testCloser{}, &testCloser{}
+	l.Add(1, old)
+	if l.Add(1, same) {
+		t.Fatalf("should not evict")
+	}
+	if old.closed != 1 || len(evicted) != 1 {
+		t.Fatalf("replaced value should be closed: %v %v", old.closed, evicted)
+	}
+
+	// adding the same value again keeps it open
+	l.Add(1, same)
+	if res := l.Set(1, same); !res.Existed || res.Previous != same {
+		t.Fatalf("bad set: %v", res)
+	}
+	l.AddToBack(1, same)
+	if same.closed != 0 {
+		t.Fatalf("should not close the value kept")
+	}
+
+	next := &testCloser{}
+	l.AddToBack(1, next)
+	if same.closed != 1 || next.closed != 0 {
+		t.Fatalf("bad closes: %v %v", same.closed, next.closed)
+	}
+	if v, ok := l.Peek(1); !ok || v != next {
+		t.Fatalf("bad value: %v", v)
+	}
+}
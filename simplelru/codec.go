@@ -0,0 +1,131 @@
+package simplelru
+
+import "errors"
+
+// CodecLRU is a non-thread safe fixed size LRU cache that stores its values
+// encoded, e.g. compressed, and decodes them on read, trading CPU for memory
+// when values are large. Bytes reports the total size of the encoded values,
+// so that callers can account for the memory held by the cache.
+//
+// Every Get and Peek decodes the stored bytes, and every Add encodes the
+// value, so both should be cheap relative to the cost of rebuilding the
+// values. The eviction callback receives decoded values, and values are
+// only decoded for it when it is set. decode must be able to decode
+// anything encode returns.
+type CodecLRU[Key comparable, Value any] struct {
+	lru     *LRU[Key, []byte]
+	encode  func(Value) []byte
+	decode  func([]byte) Value
+	bytes   int
+	onEvict EvictCallback[Key, Value]
+}
+
+// NewCodecLRU constructs a CodecLRU of the given size, storing values as
+// returned by encode.
+func NewCodecLRU[Key comparable, Value any](size int, encode func(Value) []byte, decode func([]byte) Value, onEvict EvictCallback[Key, Value]) (*CodecLRU[Key, Value], error) {
+	if encode == nil || decode == nil {
+		return nil, errors.New("must provide encode and decode functions")
+	}
+	c := &CodecLRU[Key, Value]{
+		encode:  encode,
+		decode:  decode,
+		onEvict: onEvict,
+	}
+	lru, err := NewLRU(size, c.evicted)
+	if err != nil {
+		return nil, err
+	}
+	c.lru = lru
+	return c, nil
+}
+
+// evicted accounts for an entry leaving the cache.
+func (c *CodecLRU[Key, Value]) evicted(key Key, data []byte) {
+	c.bytes -= len(data)
+	if c.onEvict != nil {
+		c.onEvict(key, c.decode(data))
+	}
+}
+
+// Bytes returns the total size of the encoded values in the cache.
+func (c *CodecLRU[Key, Value]) Bytes() int {
+	return c.bytes
+}
+
+// Add encodes and adds a value to the cache. Returns true if an eviction
+// occurred.
+func (c *CodecLRU[Key, Value]) Add(key Key, value Value) (evicted bool) {
+	data := c.encode(value)
+	if old, ok := c.lru.Peek(key); ok {
+		c.bytes -= len(old)
+	}
+	c.bytes += len(data)
+	return c.lru.Add(key, data)
+}
+
+// Get looks up and decodes a key's value from the cache.
+func (c *CodecLRU[Key, Value]) Get(key Key) (value Value, ok bool) {
+	if data, ok := c.lru.Get(key); ok {
+		return c.decode(data), true
+	}
+	return
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (c *CodecLRU[Key, Value]) Contains(key Key) (ok bool) {
+	return c.lru.Contains(key)
+}
+
+// Peek returns the decoded key value (or undefined if not found) without
+// updating the "recently used"-ness of the key.
+func (c *CodecLRU[Key, Value]) Peek(key Key) (value Value, ok bool) {
+	if data, ok := c.lru.Peek(key); ok {
+		return c.decode(data), true
+	}
+	return
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *CodecLRU[Key, Value]) Remove(key Key) (present bool) {
+	return c.lru.Remove(key)
+}
+
+// RemoveOldest removes the oldest item from the cache.
+func (c *CodecLRU[Key, Value]) RemoveOldest() (key Key, value Value, ok bool) {
+	key, data, ok := c.lru.RemoveOldest()
+	if ok {
+		value = c.decode(data)
+	}
+	return
+}
+
+// GetOldest returns the oldest entry
+func (c *CodecLRU[Key, Value]) GetOldest() (key Key, value Value, ok bool) {
+	key, data, ok := c.lru.GetOldest()
+	if ok {
+		value = c.decode(data)
+	}
+	return
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *CodecLRU[Key, Value]) Keys() []Key {
+	return c.lru.Keys()
+}
+
+// Len returns the number of items in the cache.
+func (c *CodecLRU[Key, Value]) Len() int {
+	return c.lru.Len()
+}
+
+// Purge is used to completely clear the cache.
+func (c *CodecLRU[Key, Value]) Purge() {
+	c.lru.Purge()
+}
+
+// Resize changes the cache size.
+func (c *CodecLRU[Key, Value]) Resize(size int) (evicted int) {
+	return c.lru.Resize(size)
+}
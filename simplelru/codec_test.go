@@ -0,0 +1,76 @@
+package simplelru
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"strings"
+	"testing"
+)
+
+func deflateString(s string) []byte {
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestCompression)
+	w.Write([]byte(s))
+	w.Close()
+	return buf.Bytes()
+}
+
+func inflateString(b []byte) string {
+	data, _ := io.ReadAll(flate.NewReader(bytes.NewReader(b)))
+	return string(data)
+}
+
+func TestCodecLRU(t *testing.T) {
+	var evicted []string
+	l, err := NewCodecLRU(2, deflateString, inflateString, func(k int, v string) {
+		evicted = append(evicted, v)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	big := strings.Repeat("abcd", 1000)
+	l.Add(1, big)
+	if v, ok := l.Get(1); !ok || v != big {
+		t.Fatalf("bad round trip")
+	}
+	if v, ok := l.Peek(1); !ok || v != big {
+		t.Fatalf("bad round trip")
+	}
+	if n := l.Bytes(); n != len(deflateString(big)) || n >= len(big) {
+		t.Fatalf("bad bytes: %v", n)
+	}
+
+	l.Add(1, "x")
+	l.Add(2, "yy")
+	want := len(deflateString("x")) + len(deflateString("yy"))
+	if l.Bytes() != want {
+		t.Fatalf("bad bytes: %v != %v", l.Bytes(), want)
+	}
+
+	l.Add(3, "zzz")
+	if len(evicted) != 1 || evicted[0] != "x" {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	if k, v, ok := l.GetOldest(); !ok || k != 2 || v != "yy" {
+		t.Fatalf("bad oldest: %v %v %v", k, v, ok)
+	}
+	l.Remove(2)
+	if l.Bytes() != len(deflateString("zzz")) {
+		t.Fatalf("bad bytes: %v", l.Bytes())
+	}
+	l.Purge()
+	if l.Bytes() != 0 || l.Len() != 0 {
+		t.Fatalf("bad bytes: %v", l.Bytes())
+	}
+}
+
+func TestCodecLRU_Invalid(t *testing.T) {
+	if _, err := NewCodecLRU[int, string](1, nil, inflateString, nil); err == nil {
+		t.Fatalf("should fail without encode")
+	}
+	if _, err := NewCodecLRU[int, string](0, deflateString, inflateString, nil); err == nil {
+		t.Fatalf("should fail with invalid size")
+	}
+}
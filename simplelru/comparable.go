@@ -0,0 +1,49 @@
+package simplelru
+
+// ComparableLRU is an LRU whose values are comparable, which allows
+// operations that compare values with each other.
+type ComparableLRU[Key comparable, Value comparable] struct {
+	*LRU[Key, Value]
+}
+
+// NewComparableLRU constructs a ComparableLRU of the given size.
+func NewComparableLRU[Key comparable, Value comparable](size int, onEvict EvictCallback[Key, Value]) (*ComparableLRU[Key, Value], error) {
+	lru, err := NewLRU(size, onEvict)
+	if err != nil {
+		return nil, err
+	}
+	return &ComparableLRU[Key, Value]{lru}, nil
+}
+
+// ValueDuplication returns the number of distinct values among the entries
+// of the cache, and the number of entries. A large gap between the two
+// means many entries hold equal values, and that interning values would save
+// memory. This is a diagnostic: it walks all entries and builds a temporary
+// set of the distinct values, so it costs O(n) time and memory.
+//
+// If Value is an interface type, values whose dynamic type is not
+// comparable, such as slices or maps, cannot be compared with each other
+// and each count as a distinct value.
+func (c *ComparableLRU[Key, Value]) ValueDuplication() (distinctValues, totalEntries int) {
+	distinct := make(map[Value]struct{})
+	uncomparable := 0
+	for _, ent := range c.items {
+		if !addDistinct(distinct, ent.Value.(*entry[Key, Value]).value) {
+			uncomparable++
+		}
+	}
+	return len(distinct) + uncomparable, len(c.items)
+}
+
+// addDistinct adds value to the set, or reports false if value is an
+// interface holding a value of a type that is not comparable, on which the
+// map insertion panics.
+func addDistinct[Value comparable](set map[Value]struct{}, value Value) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	set[value] = struct{}{}
+	return true
+}
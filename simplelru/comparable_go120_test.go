@@ -0,0 +1,21 @@
+//go:build go1.20
+
+package simplelru
+
+import "testing"
+
+// Interface types such as any satisfy comparable from Go 1.20 on.
+func TestComparableLRU_ValueDuplicationInterface(t *testing.T) {
+	l, err := NewComparableLRU[int, any](8, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(0, "fizz")
+	l.Add(1, "fizz")
+	l.Add(2, []int{1})
+	l.Add(3, []int{1})
+	l.Add(4, map[int]int{})
+	if d, n := l.ValueDuplication(); d != 4 || n != 5 {
+		t.Fatalf("bad: %v %v", d, n)
+	}
+}
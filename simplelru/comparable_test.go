@@ -0,0 +1,35 @@
+package simplelru
+
+import "testing"
+
+func TestComparableLRU_ValueDuplication(t *testing.T) {
+	l, err := NewComparableLRU[int, string](8, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if d, n := l.ValueDuplication(); d != 0 || n != 0 {
+		t.Fatalf("bad: %v %v", d, n)
+	}
+
+	for i := 0; i < 8; i++ {
+		if i%3 == 0 {
+			l.Add(i, "fizz")
+		} else {
+			l.Add(i, "buzz")
+		}
+	}
+	if d, n := l.ValueDuplication(); d != 2 || n != 8 {
+		t.Fatalf("bad: %v %v", d, n)
+	}
+
+	l.Add(8, "other") // evicts 0
+	if d, n := l.ValueDuplication(); d != 3 || n != 8 {
+		t.Fatalf("bad: %v %v", d, n)
+	}
+}
+
+func TestComparableLRU_Invalid(t *testing.T) {
+	if _, err := NewComparableLRU[int, int](0, nil); err == nil {
+		t.Fatalf("should fail with invalid size")
+	}
+}
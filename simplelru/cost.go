@@ -0,0 +1,227 @@
+package simplelru
+
+import (
+	"container/list"
+	"errors"
+)
+
+// CostLRU is a non-thread safe fixed size cache that blends recency with the
+// cost of rebuilding entries, in the manner of Greedy-Dual-Size-Frequency.
+// When an entry must be evicted, only the window oldest entries are
+// candidates, and among them the one with the lowest priority is evicted,
+// the oldest one on ties. An entry's priority is its rebuild cost plus the
+// cache's inflation value at the time it was last added or read, and the
+// inflation value rises to the priority of every evicted entry.
+//
+// The window keeps recency in charge: recently used entries are never
+// evicted just for being cheap. The newest entry is never a candidate, even
+// if the window spans the whole cache, so an Add never evicts the entry it
+// adds. The inflation keeps cost from dominating
+// forever: an expensive entry that is no longer used falls behind the
+// priority of newly used entries, so it is eventually evicted instead of
+// being kept indefinitely at the back of the cache. A window of 1 behaves
+// like a plain LRU.
+type CostLRU[Key comparable, Value any] struct {
+	size      int
+	window    int
+	inflation int64
+	evictList *list.List
+	items     map[Key]*list.Element
+	onEvict   EvictCallback[Key, Value]
+}
+
+// costEntry is used to hold a value in the evictList
+type costEntry[Key, Value any] struct {
+	key      Key
+	value    Value
+	cost     int64
+	priority int64
+}
+
+// NewCostLRU constructs a CostLRU of the given size, choosing eviction
+// victims among the window oldest entries.
+func NewCostLRU[Key comparable, Value any](size, window int, onEvict EvictCallback[Key, Value]) (*CostLRU[Key, Value], error) {
+	if size <= 0 {
+		return nil, errors.New("must provide a positive size")
+	}
+	if window <= 0 {
+		return nil, errors.New("must provide a positive window")
+	}
+	c := &CostLRU[Key, Value]{
+		size:      size,
+		window:    window,
+		evictList: list.New(),
+		items:     make(map[Key]*list.Element),
+		onEvict:   onEvict,
+	}
+	return c, nil
+}
+
+// SetWindow changes the number of oldest entries considered for eviction.
+// Non-positive values are ignored.
+func (c *CostLRU[Key, Value]) SetWindow(window int) {
+	if window > 0 {
+		c.window = window
+	}
+}
+
+// Purge is used to completely clear the cache.
+func (c *CostLRU[Key, Value]) Purge() {
+	for k, v := range c.items {
+		delete(c.items, k)
+		if c.onEvict != nil {
+			c.onEvict(k, v.Value.(*costEntry[Key, Value]).value)
+		}
+	}
+	c.evictList.Init()
+}
+
+// Add adds a value to the cache with a rebuild cost of 0, keeping the cost
+// of an existing entry. Returns true if an eviction occurred.
+func (c *CostLRU[Key, Value]) Add(key Key, value Value) (evicted bool) {
+	if ent, ok := c.items[key]; ok {
+		return c.AddWithCost(key, value, ent.Value.(*costEntry[Key, Value]).cost)
+	}
+	return c.AddWithCost(key, value, 0)
+}
+
+// AddWithCost adds a value to the cache with the given rebuild cost.
+// Returns true if an eviction occurred.
+func (c *CostLRU[Key, Value]) AddWithCost(key Key, value Value, rebuildCost int64) (evicted bool) {
+	// Check for existing item
+	if ent, ok := c.items[key]; ok {
+		c.evictList.MoveToFront(ent)
+		kv := ent.Value.(*costEntry[Key, Value])
+		kv.value = value
+		kv.cost = rebuildCost
+		kv.priority = c.inflation + rebuildCost
+		return false
+	}
+
+	// Add new item
+	ent := &costEntry[Key, Value]{key, value, rebuildCost, c.inflation + rebuildCost}
+	c.items[key] = c.evictList.PushFront(ent)
+
+	evict := c.evictList.Len() > c.size
+	// Verify size not exceeded
+	if evict {
+		c.evict()
+	}
+	return evict
+}
+
+// Get looks up a key's value from the cache, refreshing its priority.
+func (c *CostLRU[Key, Value]) Get(key Key) (value Value, ok bool) {
+	if ent, ok := c.items[key]; ok {
+		c.evictList.MoveToFront(ent)
+		kv := ent.Value.(*costEntry[Key, Value])
+		kv.priority = c.inflation + kv.cost
+		return kv.value, true
+	}
+	return
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (c *CostLRU[Key, Value]) Contains(key Key) (ok bool) {
+	_, ok = c.items[key]
+	return ok
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *CostLRU[Key, Value]) Peek(key Key) (value Value, ok bool) {
+	if ent, ok := c.items[key]; ok {
+		return ent.Value.(*costEntry[Key, Value]).value, true
+	}
+	return
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *CostLRU[Key, Value]) Remove(key Key) (present bool) {
+	if ent, ok := c.items[key]; ok {
+		c.removeElement(ent)
+		return true
+	}
+	return false
+}
+
+// RemoveOldest removes the least recently used item from the cache,
+// regardless of its cost.
+func (c *CostLRU[Key, Value]) RemoveOldest() (key Key, value Value, ok bool) {
+	if ent := c.evictList.Back(); ent != nil {
+		c.removeElement(ent)
+		kv := ent.Value.(*costEntry[Key, Value])
+		return kv.key, kv.value, true
+	}
+	return
+}
+
+// GetOldest returns the least recently used entry
+func (c *CostLRU[Key, Value]) GetOldest() (key Key, value Value, ok bool) {
+	if ent := c.evictList.Back(); ent != nil {
+		kv := ent.Value.(*costEntry[Key, Value])
+		return kv.key, kv.value, true
+	}
+	return
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *CostLRU[Key, Value]) Keys() []Key {
+	keys := make([]Key, 0, len(c.items))
+	for ent := c.evictList.Back(); ent != nil; ent = ent.Prev() {
+		keys = append(keys, ent.Value.(*costEntry[Key, Value]).key)
+	}
+	return keys
+}
+
+// Len returns the number of items in the cache.
+func (c *CostLRU[Key, Value]) Len() int {
+	return c.evictList.Len()
+}
+
+// Resize changes the cache size, evicting entries by priority as needed.
+func (c *CostLRU[Key, Value]) Resize(size int) (evicted int) {
+	diff := c.Len() - size
+	if diff < 0 {
+		diff = 0
+	}
+	for i := 0; i < diff; i++ {
+		c.evict()
+	}
+	c.size = size
+	return diff
+}
+
+// evict removes the lowest priority entry among the window oldest ones,
+// leaving out the newest entry unless it is the only one, and raises the
+// inflation value to its priority.
+func (c *CostLRU[Key, Value]) evict() {
+	victim := c.evictList.Back()
+	if victim == nil {
+		return
+	}
+	newest := c.evictList.Front()
+	i := 1
+	for ent := victim.Prev(); ent != nil && ent != newest && i < c.window; ent = ent.Prev() {
+		if ent.Value.(*costEntry[Key, Value]).priority < victim.Value.(*costEntry[Key, Value]).priority {
+			victim = ent
+		}
+		i++
+	}
+	if p := victim.Value.(*costEntry[Key, Value]).priority; p > c.inflation {
+		c.inflation = p
+	}
+	c.removeElement(victim)
+}
+
+// removeElement is used to remove a given list element from the cache
+func (c *CostLRU[Key, Value]) removeElement(e *list.Element) {
+	c.evictList.Remove(e)
+	kv := e.Value.(*costEntry[Key, Value])
+	delete(c.items, kv.key)
+	if c.onEvict != nil {
+		c.onEvict(kv.key, kv.value)
+	}
+}
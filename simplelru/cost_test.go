@@ -0,0 +1,105 @@
+package simplelru
+
+import "testing"
+
+func TestCostLRU(t *testing.T) {
+	var evicted []int
+	l, err := NewCostLRU(4, 3, func(k int, v int) {
+		evicted = append(evicted, k)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.AddWithCost(1, 1, 100)
+	l.AddWithCost(2, 2, 5)
+	l.AddWithCost(3, 3, 50)
+	l.AddWithCost(4, 4, 1)
+
+	// the cheapest of the 3 oldest goes, even if not the oldest
+	l.AddWithCost(5, 5, 100)
+	if len(evicted) != 1 || evicted[0] != 2 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	l.AddWithCost(6, 6, 100)
+	if len(evicted) != 2 || evicted[1] != 4 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+
+	// entries outside the window are safe, however cheap
+	l.AddWithCost(7, 7, 0)
+	if len(evicted) != 3 || evicted[2] != 3 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	l.AddWithCost(8, 8, 100)
+	if len(evicted) != 4 || evicted[3] != 1 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	if !l.Contains(7) {
+		t.Fatalf("7 should not be evicted yet")
+	}
+}
+
+func TestCostLRU_Inflation(t *testing.T) {
+	l, err := NewCostLRU[int, int](2, 2, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// an expensive entry that is never read again is eventually evicted
+	l.AddWithCost(0, 0, 50)
+	for i := 1; i < 100 && l.Contains(0); i++ {
+		l.AddWithCost(i, i, 10)
+		l.Get(i)
+	}
+	if l.Contains(0) {
+		t.Fatalf("expensive entry should age out")
+	}
+}
+
+func TestCostLRU_Window(t *testing.T) {
+	l, err := NewCostLRU[int, int](3, 1, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.AddWithCost(1, 1, 100)
+	l.AddWithCost(2, 2, 1)
+	l.AddWithCost(3, 3, 1)
+	l.AddWithCost(4, 4, 1)
+	if l.Contains(1) {
+		t.Fatalf("window of 1 should behave like a plain LRU")
+	}
+
+	l.SetWindow(3)
+	l.AddWithCost(1, 1, 100)
+	if n := l.Resize(1); n != 2 {
+		t.Fatalf("bad resize: %v", n)
+	}
+	if !l.Contains(1) {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+}
+
+func TestCostLRU_WindowSpansCache(t *testing.T) {
+	l, err := NewCostLRU[int, int](2, 8, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.AddWithCost(1, 1, 10)
+	l.AddWithCost(2, 2, 10)
+	if !l.AddWithCost(3, 3, 0) {
+		t.Fatalf("should evict")
+	}
+	if !l.Contains(3) || l.Contains(1) || !l.Contains(2) {
+		t.Fatalf("should not evict the added entry: %v", l.Keys())
+	}
+}
+
+func TestCostLRU_Invalid(t *testing.T) {
+	if _, err := NewCostLRU[int, int](0, 1, nil); err == nil {
+		t.Fatalf("should fail with invalid size")
+	}
+	if _, err := NewCostLRU[int, int](1, 0, nil); err == nil {
+		t.Fatalf("should fail with invalid window")
+	}
+}
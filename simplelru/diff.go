@@ -0,0 +1,83 @@
+package simplelru
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CacheDiff describes the differences between two caches, as computed by
+// Diff. Each slice lists keys from oldest to newest.
+type CacheDiff[Key comparable, Value any] struct {
+	// Added lists the keys that are only in the second cache.
+	Added []Key
+	// Removed lists the keys that are only in the first cache.
+	Removed []Key
+	// Changed lists the keys that are in both caches with different values.
+	Changed []Key
+	// Reordered lists the keys that are in both caches, but whose position
+	// among the keys in both caches differs in recency order.
+	Reordered []Key
+}
+
+// Diff compares two caches, e.g. the state of a cache before and after an
+// operation in a test. valueEqual compares the values of keys present in
+// both caches, so that values do not need to be comparable. Diff is O(n),
+// but allocates a map over all keys, and is meant for tests and debugging.
+func Diff[Key comparable, Value any](a, b *LRU[Key, Value], valueEqual func(Value, Value) bool) CacheDiff[Key, Value] {
+	var d CacheDiff[Key, Value]
+
+	// rank the keys common to both caches in the recency order of a
+	rank := make(map[Key]int, a.Len())
+	for ent := a.evictList.Back(); ent != nil; ent = ent.Prev() {
+		key := ent.Value.(*entry[Key, Value]).key
+		if b.Contains(key) {
+			rank[key] = len(rank)
+		} else {
+			d.Removed = append(d.Removed, key)
+		}
+	}
+
+	i := 0
+	for ent := b.evictList.Back(); ent != nil; ent = ent.Prev() {
+		kv := ent.Value.(*entry[Key, Value])
+		r, ok := rank[kv.key]
+		if !ok {
+			d.Added = append(d.Added, kv.key)
+			continue
+		}
+		if r != i {
+			d.Reordered = append(d.Reordered, kv.key)
+		}
+		i++
+		if v, _ := a.Peek(kv.key); !valueEqual(v, kv.value) {
+			d.Changed = append(d.Changed, kv.key)
+		}
+	}
+	return d
+}
+
+// Empty reports whether the diff found no differences.
+func (d CacheDiff[Key, Value]) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && len(d.Reordered) == 0
+}
+
+// String renders the diff for humans, listing each non-empty kind of
+// difference, like
+//
+//	added: [4 5], changed: [2]
+func (d CacheDiff[Key, Value]) String() string {
+	if d.Empty() {
+		return "no differences"
+	}
+	var parts []string
+	add := func(name string, keys []Key) {
+		if len(keys) > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %v", name, keys))
+		}
+	}
+	add("added", d.Added)
+	add("removed", d.Removed)
+	add("changed", d.Changed)
+	add("reordered", d.Reordered)
+	return strings.Join(parts, ", ")
+}
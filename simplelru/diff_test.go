@@ -0,0 +1,54 @@
+package simplelru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a, err := NewLRU[int, []int](8, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	b, err := NewLRU[int, []int](8, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	valueEqual := func(x, y []int) bool { return reflect.DeepEqual(x, y) }
+
+	for i := 1; i <= 4; i++ {
+		a.Add(i, []int{i})
+		b.Add(i, []int{i})
+	}
+	d := Diff(a, b, valueEqual)
+	if !d.Empty() || d.String() != "no differences" {
+		t.Fatalf("bad diff: %v", d)
+	}
+
+	b.Remove(1)
+	b.Add(2, []int{20})
+	b.Add(5, []int{5})
+	d = Diff(a, b, valueEqual)
+	want := CacheDiff[int, []int]{
+		Added:     []int{5},
+		Removed:   []int{1},
+		Changed:   []int{2},
+		Reordered: []int{3, 4, 2},
+	}
+	if !reflect.DeepEqual(d, want) {
+		t.Fatalf("bad diff: %v", d)
+	}
+	if s := d.String(); s != "added: [5], removed: [1], changed: [2], reordered: [3 4 2]" {
+		t.Fatalf("bad string: %s", s)
+	}
+
+	// same membership, different recency
+	b.Purge()
+	for _, i := range []int{1, 2, 4, 3} {
+		b.Add(i, []int{i})
+	}
+	d = Diff(a, b, valueEqual)
+	if len(d.Added)+len(d.Removed)+len(d.Changed) != 0 || !reflect.DeepEqual(d.Reordered, []int{4, 3}) {
+		t.Fatalf("bad diff: %v", d)
+	}
+}
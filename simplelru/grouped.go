@@ -0,0 +1,130 @@
+package simplelru
+
+import "errors"
+
+// GroupedLRU is a non-thread safe fixed size LRU cache that indexes its
+// entries by a group derived from their value, so that all entries of a
+// group can be retrieved in time proportional to the size of the group.
+type GroupedLRU[Key comparable, Group comparable, Value any] struct {
+	lru       *LRU[Key, Value]
+	groupFunc func(Value) Group
+	groups    map[Group]map[Key]struct{}
+}
+
+// NewGroupedLRU constructs a GroupedLRU of the given size. groupFunc derives
+// the group of a value and must always return the same group for the same
+// value.
+func NewGroupedLRU[Key comparable, Group comparable, Value any](size int, groupFunc func(Value) Group) (*GroupedLRU[Key, Group, Value], error) {
+	if groupFunc == nil {
+		return nil, errors.New("must provide a group function")
+	}
+	c := &GroupedLRU[Key, Group, Value]{
+		groupFunc: groupFunc,
+		groups:    make(map[Group]map[Key]struct{}),
+	}
+	lru, err := NewLRU(size, c.removeFromGroup)
+	if err != nil {
+		return nil, err
+	}
+	c.lru = lru
+	return c, nil
+}
+
+// addToGroup indexes the key under the group of its value.
+func (c *GroupedLRU[Key, Group, Value]) addToGroup(key Key, value Value) {
+	g := c.groupFunc(value)
+	keys, ok := c.groups[g]
+	if !ok {
+		keys = make(map[Key]struct{})
+		c.groups[g] = keys
+	}
+	keys[key] = struct{}{}
+}
+
+// removeFromGroup drops the key from the group of its value, and the group
+// itself once it is empty. It is used as the eviction callback, so it runs
+// for every entry leaving the cache.
+func (c *GroupedLRU[Key, Group, Value]) removeFromGroup(key Key, value Value) {
+	g := c.groupFunc(value)
+	keys := c.groups[g]
+	delete(keys, key)
+	if len(keys) == 0 {
+		delete(c.groups, g)
+	}
+}
+
+// Add adds a value to the cache, moving the key to the group of the new
+// value. Returns true if an eviction occurred.
+func (c *GroupedLRU[Key, Group, Value]) Add(key Key, value Value) (evicted bool) {
+	if old, ok := c.lru.Peek(key); ok {
+		c.removeFromGroup(key, old)
+	}
+	evicted = c.lru.Add(key, value)
+	c.addToGroup(key, value)
+	return evicted
+}
+
+// Get looks up a key's value from the cache.
+func (c *GroupedLRU[Key, Group, Value]) Get(key Key) (value Value, ok bool) {
+	return c.lru.Get(key)
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (c *GroupedLRU[Key, Group, Value]) Contains(key Key) (ok bool) {
+	return c.lru.Contains(key)
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *GroupedLRU[Key, Group, Value]) Peek(key Key) (value Value, ok bool) {
+	return c.lru.Peek(key)
+}
+
+// PeekGroup returns all entries whose value belongs to the given group, in
+// no particular order, without updating their "recently used"-ness.
+func (c *GroupedLRU[Key, Group, Value]) PeekGroup(g Group) []Entry[Key, Value] {
+	keys := c.groups[g]
+	entries := make([]Entry[Key, Value], 0, len(keys))
+	for key := range keys {
+		value, _ := c.lru.Peek(key)
+		entries = append(entries, Entry[Key, Value]{Key: key, Value: value})
+	}
+	return entries
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *GroupedLRU[Key, Group, Value]) Remove(key Key) (present bool) {
+	return c.lru.Remove(key)
+}
+
+// RemoveOldest removes the oldest item from the cache.
+func (c *GroupedLRU[Key, Group, Value]) RemoveOldest() (key Key, value Value, ok bool) {
+	return c.lru.RemoveOldest()
+}
+
+// GetOldest returns the oldest entry
+func (c *GroupedLRU[Key, Group, Value]) GetOldest() (key Key, value Value, ok bool) {
+	return c.lru.GetOldest()
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *GroupedLRU[Key, Group, Value]) Keys() []Key {
+	return c.lru.Keys()
+}
+
+// Len returns the number of items in the cache.
+func (c *GroupedLRU[Key, Group, Value]) Len() int {
+	return c.lru.Len()
+}
+
+// Purge is used to completely clear the cache.
+func (c *GroupedLRU[Key, Group, Value]) Purge() {
+	c.lru.Purge()
+}
+
+// Resize changes the cache size.
+func (c *GroupedLRU[Key, Group, Value]) Resize(size int) (evicted int) {
+	return c.lru.Resize(size)
+}
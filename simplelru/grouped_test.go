@@ -0,0 +1,86 @@
+package simplelru
+
+import (
+	"sort"
+	"testing"
+)
+
+func groupKeys(entries []Entry[int, int]) []int {
+	keys := make([]int, 0, len(entries))
+	for _, ent := range entries {
+		keys = append(keys, ent.Key)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func TestGroupedLRU(t *testing.T) {
+	l, err := NewGroupedLRU[int, int, int](4, func(v int) int { return v % 2 })
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		l.Add(i, i)
+	}
+	if keys := groupKeys(l.PeekGroup(0)); len(keys) != 2 || keys[0] != 0 || keys[1] != 2 {
+		t.Fatalf("bad group: %v", keys)
+	}
+	if k, _, _ := l.GetOldest(); k != 0 {
+		t.Fatalf("peek group should not update recent-ness")
+	}
+
+	// evictions keep the index consistent
+	l.Add(4, 4) // evicts 0
+	l.Add(5, 5) // evicts 1
+	if keys := groupKeys(l.PeekGroup(0)); len(keys) != 2 || keys[0] != 2 || keys[1] != 4 {
+		t.Fatalf("bad group: %v", keys)
+	}
+	if keys := groupKeys(l.PeekGroup(1)); len(keys) != 2 || keys[0] != 3 || keys[1] != 5 {
+		t.Fatalf("bad group: %v", keys)
+	}
+
+	l.Remove(3)
+	l.RemoveOldest() // removes 2
+	if keys := groupKeys(l.PeekGroup(1)); len(keys) != 1 || keys[0] != 5 {
+		t.Fatalf("bad group: %v", keys)
+	}
+	if keys := groupKeys(l.PeekGroup(0)); len(keys) != 1 || keys[0] != 4 {
+		t.Fatalf("bad group: %v", keys)
+	}
+
+	l.Resize(1) // evicts 4
+	if len(l.PeekGroup(0)) != 0 {
+		t.Fatalf("group should be empty")
+	}
+
+	l.Purge()
+	if len(l.groups) != 0 {
+		t.Fatalf("groups should be empty: %v", l.groups)
+	}
+}
+
+func TestGroupedLRU_Update(t *testing.T) {
+	l, err := NewGroupedLRU[int, string, string](4, func(v string) string { return v[:1] })
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, "a1")
+	l.Add(2, "a2")
+	l.Add(1, "b1")
+
+	if ents := l.PeekGroup("a"); len(ents) != 1 || ents[0].Key != 2 {
+		t.Fatalf("bad group: %v", ents)
+	}
+	if ents := l.PeekGroup("b"); len(ents) != 1 || ents[0].Key != 1 || ents[0].Value != "b1" {
+		t.Fatalf("bad group: %v", ents)
+	}
+}
+
+func TestGroupedLRU_Invalid(t *testing.T) {
+	if _, err := NewGroupedLRU[int, int, int](4, nil); err == nil {
+		t.Fatalf("should fail without group function")
+	}
+	if _, err := NewGroupedLRU[int, int, int](0, func(v int) int { return v }); err == nil {
+		t.Fatalf("should fail with invalid size")
+	}
+}
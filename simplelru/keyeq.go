@@ -0,0 +1,205 @@
+package simplelru
+
+import (
+	"container/list"
+	"errors"
+)
+
+// KeyEqLRU is a non-thread safe fixed size LRU cache that uses caller
+// supplied hash and equality functions to identify keys, instead of the
+// == operator. This allows keys that are not comparable, and keys such as
+// interfaces or pointers whose == does not match the intended identity.
+//
+// The functions must honour the usual contract: keyEq must be an
+// equivalence relation (reflexive, symmetric and transitive), and keys that
+// are equal according to keyEq must have the same keyHash. Keys with the
+// same hash are kept in a bucket that is scanned linearly with keyEq, so
+// collisions are resolved correctly but a poor hash degrades lookups
+// towards O(n).
+type KeyEqLRU[Key, Value any] struct {
+	size      int
+	evictList *list.List
+	items     map[uint64][]*list.Element
+	keyEq     func(a, b Key) bool
+	keyHash   func(Key) uint64
+	onEvict   EvictCallback[Key, Value]
+}
+
+// NewLRUWithKeyEq constructs a KeyEqLRU of the given size.
+func NewLRUWithKeyEq[Key, Value any](size int, keyEq func(a, b Key) bool, keyHash func(Key) uint64, onEvict EvictCallback[Key, Value]) (*KeyEqLRU[Key, Value], error) {
+	if size <= 0 {
+		return nil, errors.New("must provide a positive size")
+	}
+	if keyEq == nil || keyHash == nil {
+		return nil, errors.New("must provide key equality and hash functions")
+	}
+	c := &KeyEqLRU[Key, Value]{
+		size:      size,
+		evictList: list.New(),
+		items:     make(map[uint64][]*list.Element),
+		keyEq:     keyEq,
+		keyHash:   keyHash,
+		onEvict:   onEvict,
+	}
+	return c, nil
+}
+
+// lookup returns the list element holding the given key, if any.
+func (c *KeyEqLRU[Key, Value]) lookup(key Key) *list.Element {
+	for _, e := range c.items[c.keyHash(key)] {
+		if c.keyEq(e.Value.(*entry[Key, Value]).key, key) {
+			return e
+		}
+	}
+	return nil
+}
+
+// Purge is used to completely clear the cache.
+func (c *KeyEqLRU[Key, Value]) Purge() {
+	for h, bucket := range c.items {
+		if c.onEvict != nil {
+			for _, e := range bucket {
+				kv := e.Value.(*entry[Key, Value])
+				c.onEvict(kv.key, kv.value)
+			}
+		}
+		delete(c.items, h)
+	}
+	c.evictList.Init()
+}
+
+// Add adds a value to the cache.  Returns true if an eviction occurred.
+func (c *KeyEqLRU[Key, Value]) Add(key Key, value Value) (evicted bool) {
+	// Check for existing item
+	if ent := c.lookup(key); ent != nil {
+		c.evictList.MoveToFront(ent)
+		ent.Value.(*entry[Key, Value]).value = value
+		return false
+	}
+
+	// Add new item
+	h := c.keyHash(key)
+	ent := c.evictList.PushFront(&entry[Key, Value]{key, value})
+	c.items[h] = append(c.items[h], ent)
+
+	evict := c.evictList.Len() > c.size
+	// Verify size not exceeded
+	if evict {
+		c.removeOldest()
+	}
+	return evict
+}
+
+// Get looks up a key's value from the cache.
+func (c *KeyEqLRU[Key, Value]) Get(key Key) (value Value, ok bool) {
+	if ent := c.lookup(key); ent != nil {
+		c.evictList.MoveToFront(ent)
+		return ent.Value.(*entry[Key, Value]).value, true
+	}
+	return
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (c *KeyEqLRU[Key, Value]) Contains(key Key) (ok bool) {
+	return c.lookup(key) != nil
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *KeyEqLRU[Key, Value]) Peek(key Key) (value Value, ok bool) {
+	if ent := c.lookup(key); ent != nil {
+		return ent.Value.(*entry[Key, Value]).value, true
+	}
+	return
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *KeyEqLRU[Key, Value]) Remove(key Key) (present bool) {
+	if ent := c.lookup(key); ent != nil {
+		c.removeElement(ent)
+		return true
+	}
+	return false
+}
+
+// RemoveOldest removes the oldest item from the cache.
+func (c *KeyEqLRU[Key, Value]) RemoveOldest() (key Key, value Value, ok bool) {
+	ent := c.evictList.Back()
+	if ent != nil {
+		c.removeElement(ent)
+		kv := ent.Value.(*entry[Key, Value])
+		return kv.key, kv.value, true
+	}
+	return
+}
+
+// GetOldest returns the oldest entry
+func (c *KeyEqLRU[Key, Value]) GetOldest() (key Key, value Value, ok bool) {
+	ent := c.evictList.Back()
+	if ent != nil {
+		kv := ent.Value.(*entry[Key, Value])
+		return kv.key, kv.value, true
+	}
+	return
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *KeyEqLRU[Key, Value]) Keys() []Key {
+	keys := make([]Key, 0, c.evictList.Len())
+	for ent := c.evictList.Back(); ent != nil; ent = ent.Prev() {
+		keys = append(keys, ent.Value.(*entry[Key, Value]).key)
+	}
+	return keys
+}
+
+// Len returns the number of items in the cache.
+func (c *KeyEqLRU[Key, Value]) Len() int {
+	return c.evictList.Len()
+}
+
+// Resize changes the cache size.
+func (c *KeyEqLRU[Key, Value]) Resize(size int) (evicted int) {
+	diff := c.Len() - size
+	if diff < 0 {
+		diff = 0
+	}
+	for i := 0; i < diff; i++ {
+		c.removeOldest()
+	}
+	c.size = size
+	return diff
+}
+
+// removeOldest removes the oldest item from the cache.
+func (c *KeyEqLRU[Key, Value]) removeOldest() {
+	ent := c.evictList.Back()
+	if ent != nil {
+		c.removeElement(ent)
+	}
+}
+
+// removeElement is used to remove a given list element from the cache
+func (c *KeyEqLRU[Key, Value]) removeElement(e *list.Element) {
+	c.evictList.Remove(e)
+	kv := e.Value.(*entry[Key, Value])
+	h := c.keyHash(kv.key)
+	bucket := c.items[h]
+	for i, be := range bucket {
+		if be == e {
+			bucket[i] = bucket[len(bucket)-1]
+			bucket[len(bucket)-1] = nil
+			bucket = bucket[:len(bucket)-1]
+			break
+		}
+	}
+	if len(bucket) == 0 {
+		delete(c.items, h)
+	} else {
+		c.items[h] = bucket
+	}
+	if c.onEvict != nil {
+		c.onEvict(kv.key, kv.value)
+	}
+}
@@ -0,0 +1,101 @@
+package simplelru
+
+import (
+	"strings"
+	"testing"
+)
+
+// caseInsensitiveLRU treats keys differing only in case as equal, and
+// hashes by length so that different keys collide.
+func caseInsensitiveLRU(t *testing.T, size int, onEvict EvictCallback[string, int]) *KeyEqLRU[string, int] {
+	l, err := NewLRUWithKeyEq(size, strings.EqualFold, func(k string) uint64 {
+		return uint64(len(k))
+	}, onEvict)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	return l
+}
+
+func TestKeyEqLRU(t *testing.T) {
+	var evicted []string
+	l := caseInsensitiveLRU(t, 2, func(k string, v int) {
+		evicted = append(evicted, k)
+	})
+
+	l.Add("foo", 1)
+	l.Add("FOO", 2)
+	if l.Len() != 1 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+	if v, ok := l.Get("Foo"); !ok || v != 2 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+
+	// "bar" collides with "foo" but is a different key
+	l.Add("bar", 3)
+	if v, ok := l.Peek("BAR"); !ok || v != 3 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	if v, ok := l.Peek("foo"); !ok || v != 2 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+
+	if !l.Add("baz", 4) {
+		t.Fatalf("should have an eviction")
+	}
+	if len(evicted) != 1 || evicted[0] != "foo" {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	if l.Contains("foo") || !l.Contains("bar") || !l.Contains("baz") {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+
+	if !l.Remove("BAZ") || l.Remove("baz") {
+		t.Fatalf("bad remove")
+	}
+	if keys := l.Keys(); len(keys) != 1 || keys[0] != "bar" {
+		t.Fatalf("bad keys: %v", keys)
+	}
+
+	l.Purge()
+	if l.Len() != 0 || len(l.items) != 0 {
+		t.Fatalf("should be empty")
+	}
+	if len(evicted) != 3 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+}
+
+func TestKeyEqLRU_OldestResize(t *testing.T) {
+	l := caseInsensitiveLRU(t, 3, nil)
+	l.Add("a", 1)
+	l.Add("b", 2)
+	l.Add("cc", 3)
+
+	if k, v, ok := l.GetOldest(); !ok || k != "a" || v != 1 {
+		t.Fatalf("bad oldest: %v %v %v", k, v, ok)
+	}
+	if k, _, ok := l.RemoveOldest(); !ok || k != "a" {
+		t.Fatalf("bad oldest: %v", k)
+	}
+	if n := l.Resize(1); n != 1 {
+		t.Fatalf("bad resize: %v", n)
+	}
+	if keys := l.Keys(); len(keys) != 1 || keys[0] != "cc" {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	l.RemoveOldest()
+	if _, _, ok := l.RemoveOldest(); ok {
+		t.Fatalf("should be empty")
+	}
+}
+
+func TestKeyEqLRU_Invalid(t *testing.T) {
+	if _, err := NewLRUWithKeyEq[string, int](0, strings.EqualFold, func(string) uint64 { return 0 }, nil); err == nil {
+		t.Fatalf("should fail with invalid size")
+	}
+	if _, err := NewLRUWithKeyEq[string, int](1, nil, nil, nil); err == nil {
+		t.Fatalf("should fail without key functions")
+	}
+}
@@ -3,8 +3,13 @@ package simplelru
 import (
 	"container/list"
 	"errors"
+	"fmt"
+	"strings"
 )
 
+// DefaultStringEntries is the number of entries printed by String.
+const DefaultStringEntries = 16
+
 // EvictCallback is used to get a callback when a cache entry is evicted
 type EvictCallback[Key, Value any] func(key Key, value Value)
 
@@ -22,6 +27,26 @@ type entry[Key, Value any] struct {
 	value Value
 }
 
+// Entry is a key/value pair held by a cache.
+type Entry[Key, Value any] struct {
+	Key   Key
+	Value Value
+}
+
+// SetResult describes the outcome of Set.
+type SetResult[Key, Value any] struct {
+	// Previous is the value replaced by Set, or the zero value if the key
+	// was new.
+	Previous Value
+	// Existed reports whether the key was already in the cache.
+	Existed bool
+	// Evicted reports whether adding a new key evicted an entry.
+	Evicted bool
+	// EvictedKey is the key of the evicted entry, or the zero value if
+	// Evicted is false.
+	EvictedKey Key
+}
+
 // NewLRU constructs an LRU of the given size
 func NewLRU[Key comparable, Value any](size int, onEvict EvictCallback[Key, Value]) (*LRU[Key, Value], error) {
 	if size <= 0 {
@@ -36,17 +61,47 @@ func NewLRU[Key comparable, Value any](size int, onEvict EvictCallback[Key, Valu
 	return c, nil
 }
 
+// NewLRUFromEntries constructs an LRU of the given size holding the given
+// entries, which are ordered from oldest to newest. If there are more
+// entries than fit, only the newest are kept, and the eviction callback is
+// not invoked for the others. A key appearing more than once takes its last
+// value and position.
+func NewLRUFromEntries[Key comparable, Value any](size int, entries []Entry[Key, Value], onEvict EvictCallback[Key, Value]) (*LRU[Key, Value], error) {
+	c, err := NewLRU[Key, Value](size, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, ent := range entries {
+		c.Add(ent.Key, ent.Value)
+	}
+	c.onEvict = onEvict
+	return c, nil
+}
+
 // Purge is used to completely clear the cache.
 func (c *LRU[Key, Value]) Purge() {
 	for k, v := range c.items {
+		delete(c.items, k)
 		if c.onEvict != nil {
 			c.onEvict(k, v.Value.(*entry[Key, Value]).value)
 		}
-		delete(c.items, k)
 	}
 	c.evictList.Init()
 }
 
+// Drain removes all entries from the cache without invoking the eviction
+// callback, returning them from oldest to newest.
+func (c *LRU[Key, Value]) Drain() []Entry[Key, Value] {
+	entries := make([]Entry[Key, Value], 0, c.evictList.Len())
+	for ent := c.evictList.Back(); ent != nil; ent = ent.Prev() {
+		kv := ent.Value.(*entry[Key, Value])
+		entries = append(entries, Entry[Key, Value]{Key: kv.key, Value: kv.value})
+	}
+	c.items = make(map[Key]*list.Element)
+	c.evictList.Init()
+	return entries
+}
+
 // Add adds a value to the cache.  Returns true if an eviction occurred.
 func (c *LRU[Key, Value]) Add(key Key, value Value) (evicted bool) {
 	// Check for existing item
@@ -69,6 +124,78 @@ func (c *LRU[Key, Value]) Add(key Key, value Value) (evicted bool) {
 	return evict
 }
 
+// GetOrAddEvict returns the existing value for the key if present, updating
+// its "recently used"-ness. Otherwise it adds the value, and if this evicts
+// an entry, returns the evicted key. evictedKey is the zero value when
+// evicted is false.
+func (c *LRU[Key, Value]) GetOrAddEvict(key Key, value Value) (actual Value, loaded bool, evictedKey Key, evicted bool) {
+	// Check for existing item
+	if ent, ok := c.items[key]; ok {
+		c.evictList.MoveToFront(ent)
+		return ent.Value.(*entry[Key, Value]).value, true, evictedKey, false
+	}
+
+	// Add new item
+	ent := &entry[Key, Value]{key, value}
+	c.items[key] = c.evictList.PushFront(ent)
+
+	// Verify size not exceeded
+	if c.evictList.Len() > c.size {
+		oldest := c.evictList.Back()
+		evictedKey = oldest.Value.(*entry[Key, Value]).key
+		c.removeElement(oldest)
+		evicted = true
+	}
+	return value, false, evictedKey, evicted
+}
+
+// Set adds a value to the cache like Add, updating the "recently used"-ness
+// of the key, and reports the previous value and the eviction it caused.
+func (c *LRU[Key, Value]) Set(key Key, value Value) (result SetResult[Key, Value]) {
+	// Check for existing item
+	if ent, ok := c.items[key]; ok {
+		c.evictList.MoveToFront(ent)
+		kv := ent.Value.(*entry[Key, Value])
+		result.Previous, result.Existed = kv.value, true
+		kv.value = value
+		return result
+	}
+
+	// Add new item
+	ent := &entry[Key, Value]{key, value}
+	c.items[key] = c.evictList.PushFront(ent)
+
+	// Verify size not exceeded
+	if c.evictList.Len() > c.size {
+		oldest := c.evictList.Back()
+		result.EvictedKey = oldest.Value.(*entry[Key, Value]).key
+		result.Evicted = true
+		c.removeElement(oldest)
+	}
+	return result
+}
+
+// AddToBack adds a value to the cache as the oldest entry, so that it is the
+// first to be evicted. If the key is already present its value is updated
+// without changing its position. Returns true if an eviction occurred.
+func (c *LRU[Key, Value]) AddToBack(key Key, value Value) (evicted bool) {
+	// Check for existing item
+	if ent, ok := c.items[key]; ok {
+		ent.Value.(*entry[Key, Value]).value = value
+		return false
+	}
+
+	// Make room first, otherwise the new item would evict itself
+	evict := c.evictList.Len() >= c.size
+	if evict {
+		c.removeOldest()
+	}
+
+	ent := &entry[Key, Value]{key, value}
+	c.items[key] = c.evictList.PushBack(ent)
+	return evict
+}
+
 // Get looks up a key's value from the cache.
 func (c *LRU[Key, Value]) Get(key Key) (value Value, ok bool) {
 	if ent, ok := c.items[key]; ok {
@@ -99,6 +226,17 @@ func (c *LRU[Key, Value]) Peek(key Key) (value Value, ok bool) {
 	return
 }
 
+// TestFunc reports whether the key is in the cache and, if so, whether its
+// value satisfies the predicate. Like Peek, it is a pure read that does not
+// update the "recently used"-ness of the key.
+func (c *LRU[Key, Value]) TestFunc(key Key, predicate func(Value) bool) (present, satisfied bool) {
+	ent, ok := c.items[key]
+	if !ok {
+		return false, false
+	}
+	return true, predicate(ent.Value.(*entry[Key, Value]).value)
+}
+
 // Remove removes the provided key from the cache, returning if the
 // key was contained.
 func (c *LRU[Key, Value]) Remove(key Key) (present bool) {
@@ -147,22 +285,174 @@ func (c *LRU[Key, Value]) Keys() []Key {
 	return keys
 }
 
+// ErrCursorNotFound is returned by Page when the entry a cursor points
+// after is no longer in the cache, so there is no way to tell where its
+// page ended.
+var ErrCursorNotFound = errors.New("page cursor entry not found")
+
+// PageCursor marks where a page returned by Page ended. The zero value
+// starts from the newest entry.
+type PageCursor[Key any] struct {
+	key   Key
+	after bool
+	done  bool
+}
+
+// CursorAfter returns a cursor whose page starts after the entry of key.
+func CursorAfter[Key any](key Key) PageCursor[Key] {
+	return PageCursor[Key]{key: key, after: true}
+}
+
+// Done reports whether the page that returned the cursor was the last one.
+func (p PageCursor[Key]) Done() bool {
+	return p.done
+}
+
+// Page returns up to limit entries, from the newest to the oldest, starting
+// where cursor points, without updating their recent-ness; a non-positive
+// limit returns all of them. Pass next as the cursor of the following page
+// until next.Done() reports that no older entries follow. If the entry cursor points after is no longer in the
+// cache, Page returns ErrCursorNotFound; the caller may resume with
+// CursorAfter and an older key it still knows of, or start over.
+func (c *LRU[Key, Value]) Page(cursor PageCursor[Key], limit int) (entries []Entry[Key, Value], next PageCursor[Key], err error) {
+	if cursor.done {
+		return nil, cursor, nil
+	}
+	ent := c.evictList.Front()
+	if cursor.after {
+		item, ok := c.items[cursor.key]
+		if !ok {
+			return nil, cursor, ErrCursorNotFound
+		}
+		ent = item.Next()
+	}
+	next = cursor
+	for ; ent != nil && (limit <= 0 || len(entries) < limit); ent = ent.Next() {
+		kv := ent.Value.(*entry[Key, Value])
+		entries = append(entries, Entry[Key, Value]{Key: kv.key, Value: kv.value})
+		next = CursorAfter(kv.key)
+	}
+	next.done = ent == nil
+	return entries, next, nil
+}
+
+// NewestMatching returns up to n entries satisfying the predicate, from the
+// newest to the oldest. It walks the cache from the newest entry and stops
+// as soon as it has collected n entries, so only the entries up to the
+// oldest match are visited. Like Peek, it is a pure read that does not
+// update the "recently used"-ness of the entries.
+func (c *LRU[Key, Value]) NewestMatching(n int, predicate func(Key, Value) bool) []Entry[Key, Value] {
+	var entries []Entry[Key, Value]
+	for ent := c.evictList.Front(); ent != nil && len(entries) < n; ent = ent.Next() {
+		kv := ent.Value.(*entry[Key, Value])
+		if predicate(kv.key, kv.value) {
+			entries = append(entries, Entry[Key, Value]{Key: kv.key, Value: kv.value})
+		}
+	}
+	return entries
+}
+
+// CopyHottest adds the n most recently used entries to dst, from the oldest
+// to the newest of them, so that their relative order is preserved and the
+// hottest entry ends up as the most recently used one in dst. The entries
+// stay in the cache and their recent-ness is not updated. If n exceeds the
+// number of entries, all entries are copied.
+func (c *LRU[Key, Value]) CopyHottest(n int, dst LRUCache[Key, Value]) {
+	if n <= 0 {
+		return
+	}
+	ent := c.evictList.Front()
+	for i := 1; i < n && ent != nil && ent.Next() != nil; i++ {
+		ent = ent.Next()
+	}
+	for ; ent != nil; ent = ent.Prev() {
+		kv := ent.Value.(*entry[Key, Value])
+		dst.Add(kv.key, kv.value)
+	}
+}
+
 // Len returns the number of items in the cache.
 func (c *LRU[Key, Value]) Len() int {
 	return c.evictList.Len()
 }
 
-// Resize changes the cache size.
-func (c *LRU[Key, Value]) Resize(size int) (evicted int) {
-	diff := c.Len() - size
-	if diff < 0 {
-		diff = 0
+// EvictionCountdown returns the number of new keys that can be added before
+// the key is evicted, assuming it is not accessed in the meantime, which
+// would reset its countdown. The oldest entry of a full cache has a
+// countdown of 1. It walks the cache from the oldest entry, so it is O(n).
+func (c *LRU[Key, Value]) EvictionCountdown(key Key) (adds int, ok bool) {
+	if _, ok := c.items[key]; !ok {
+		return 0, false
+	}
+	adds = c.size - c.evictList.Len() + 1
+	for ent := c.evictList.Back(); ent.Value.(*entry[Key, Value]).key != key; ent = ent.Prev() {
+		adds++
+	}
+	return adds, true
+}
+
+// PeekVictim reports the entry that adding newKey would evict, without
+// updating the cache. wouldEvict is false if newKey is already in the cache,
+// since adding it only updates its value, or if the cache is not full.
+func (c *LRU[Key, Value]) PeekVictim(newKey Key) (victimKey Key, victimValue Value, wouldEvict bool) {
+	if _, ok := c.items[newKey]; ok || c.evictList.Len() < c.size {
+		return
+	}
+	if ent := c.evictList.Back(); ent != nil {
+		kv := ent.Value.(*entry[Key, Value])
+		return kv.key, kv.value, true
 	}
-	for i := 0; i < diff; i++ {
+	return
+}
+
+// Cap returns the size of the cache, the number of items it holds at most.
+func (c *LRU[Key, Value]) Cap() int {
+	return c.size
+}
+
+// Resize changes the cache size, returning the number of entries evicted to
+// fit. Entries that the eviction callback removes from the cache count
+// towards fitting, but not as evicted.
+func (c *LRU[Key, Value]) Resize(size int) (evicted int) {
+	for c.Len() > size {
 		c.removeOldest()
+		evicted++
 	}
 	c.size = size
-	return diff
+	return evicted
+}
+
+// String renders the cache for debugging, as with StringN using
+// DefaultStringEntries.
+func (c *LRU[Key, Value]) String() string {
+	return c.StringN(DefaultStringEntries)
+}
+
+// StringN renders the length, the capacity and at most maxEntries entries
+// of the cache, from newest to oldest, like
+//
+//	LRU(len=3/cap=5)[k3=v3, k2=v2, ...]
+//
+// using the %v format for keys and values, and ending with an ellipsis if
+// entries were left out. The output is meant for humans, not for parsing.
+func (c *LRU[Key, Value]) StringN(maxEntries int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "LRU(len=%d/cap=%d)[", c.evictList.Len(), c.size)
+	i := 0
+	for ent := c.evictList.Front(); ent != nil; ent = ent.Next() {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if i >= maxEntries {
+			b.WriteString("...")
+			break
+		}
+		kv := ent.Value.(*entry[Key, Value])
+		fmt.Fprintf(&b, "%v=%v", kv.key, kv.value)
+		i++
+	}
+	b.WriteString("]")
+	return b.String()
 }
 
 // removeOldest removes the oldest item from the cache.
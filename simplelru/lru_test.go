@@ -1,6 +1,10 @@
 package simplelru
 
-import "testing"
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
 
 func TestLRU(t *testing.T) {
 	evictCounter := 0
@@ -204,3 +208,439 @@ func TestLRU_Resize(t *testing.T) {
 		t.Errorf("Cache should have contained 2 elements")
 	}
 }
+
+func TestLRU_AddToBack(t *testing.T) {
+	l, err := NewLRU[int, int](2, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add(1, 1)
+	if l.AddToBack(2, 2) {
+		t.Fatalf("should not have an eviction")
+	}
+	if k, _, _ := l.GetOldest(); k != 2 {
+		t.Fatalf("bad oldest: %v", k)
+	}
+
+	// existing keys are updated in place
+	if l.AddToBack(1, 10) {
+		t.Fatalf("should not have an eviction")
+	}
+	if v, _ := l.Peek(1); v != 10 {
+		t.Fatalf("bad value: %v", v)
+	}
+	if k, _, _ := l.GetOldest(); k != 2 {
+		t.Fatalf("bad oldest: %v", k)
+	}
+
+	// a full cache makes room before inserting
+	if !l.AddToBack(3, 3) {
+		t.Fatalf("should have an eviction")
+	}
+	if l.Contains(2) || !l.Contains(3) || !l.Contains(1) {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+	if k, _, _ := l.GetOldest(); k != 3 {
+		t.Fatalf("bad oldest: %v", k)
+	}
+}
+
+func TestLRU_Drain(t *testing.T) {
+	evictCounter := 0
+	l, err := NewLRU(4, func(k int, v int) {
+		evictCounter++
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		l.Add(i, i*10)
+	}
+	l.Get(0)
+
+	entries := l.Drain()
+	expected := []int{1, 2, 3, 0}
+	if len(entries) != len(expected) {
+		t.Fatalf("bad entries: %v", entries)
+	}
+	for i, ent := range entries {
+		if ent.Key != expected[i] || ent.Value != expected[i]*10 {
+			t.Fatalf("bad entries: %v", entries)
+		}
+	}
+	if l.Len() != 0 || l.Contains(0) {
+		t.Fatalf("should be empty")
+	}
+	if evictCounter != 0 {
+		t.Fatalf("bad evict count: %v", evictCounter)
+	}
+
+	l.Add(5, 5)
+	if keys := l.Keys(); len(keys) != 1 || keys[0] != 5 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+}
+
+func TestLRU_GetOrAddEvict(t *testing.T) {
+	evictCounter := 0
+	l, err := NewLRU(2, func(k int, v int) {
+		evictCounter++
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	actual, loaded, evictedKey, evicted := l.GetOrAddEvict(1, 1)
+	if actual != 1 || loaded || evictedKey != 0 || evicted {
+		t.Fatalf("bad: %v %v %v %v", actual, loaded, evictedKey, evicted)
+	}
+	l.Add(2, 2)
+
+	actual, loaded, evictedKey, evicted = l.GetOrAddEvict(1, 10)
+	if actual != 1 || !loaded || evictedKey != 0 || evicted {
+		t.Fatalf("bad: %v %v %v %v", actual, loaded, evictedKey, evicted)
+	}
+	if k, _, _ := l.GetOldest(); k != 2 {
+		t.Fatalf("existing key should be promoted")
+	}
+
+	actual, loaded, evictedKey, evicted = l.GetOrAddEvict(3, 3)
+	if actual != 3 || loaded || evictedKey != 2 || !evicted {
+		t.Fatalf("bad: %v %v %v %v", actual, loaded, evictedKey, evicted)
+	}
+	if l.Contains(2) || evictCounter != 1 {
+		t.Fatalf("2 should be evicted")
+	}
+}
+
+func TestLRU_CopyHottest(t *testing.T) {
+	src, err := NewLRU[int, int](8, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 8; i++ {
+		src.Add(i, i*10)
+	}
+	src.Get(2) // order, oldest to newest: 0 1 3 4 5 6 7 2
+
+	dst, err := NewLRU[int, int](4, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	src.CopyHottest(3, dst)
+	expected := []int{6, 7, 2}
+	keys := dst.Keys()
+	if len(keys) != len(expected) {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	for i, k := range keys {
+		if k != expected[i] {
+			t.Fatalf("bad keys: %v", keys)
+		}
+		if v, _ := dst.Peek(k); v != k*10 {
+			t.Fatalf("bad value: %v", v)
+		}
+	}
+	if src.Len() != 8 {
+		t.Fatalf("source should be unchanged")
+	}
+	if k, _, _ := src.GetOldest(); k != 0 {
+		t.Fatalf("source recency should be unchanged")
+	}
+
+	dst.Purge()
+	src.CopyHottest(100, dst)
+	if keys := dst.Keys(); len(keys) != 4 || keys[0] != 5 || keys[3] != 2 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+
+	dst.Purge()
+	src.CopyHottest(0, dst)
+	if dst.Len() != 0 {
+		t.Fatalf("should copy nothing")
+	}
+}
+
+func TestLRU_Set(t *testing.T) {
+	l, err := NewLRU[int, int](2, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if r := l.Set(1, 1); r.Existed || r.Evicted || r.Previous != 0 {
+		t.Fatalf("bad result: %+v", r)
+	}
+	l.Set(2, 2)
+	if r := l.Set(1, 10); !r.Existed || r.Evicted || r.Previous != 1 {
+		t.Fatalf("bad result: %+v", r)
+	}
+
+	// the update promoted 1, so 2 is evicted
+	if r := l.Set(3, 3); r.Existed || !r.Evicted || r.EvictedKey != 2 {
+		t.Fatalf("bad result: %+v", r)
+	}
+	if v, ok := l.Peek(1); !ok || v != 10 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+}
+
+func TestLRU_String(t *testing.T) {
+	l, err := NewLRU[string, int](5, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if s := l.String(); s != "LRU(len=0/cap=5)[]" {
+		t.Fatalf("bad string: %s", s)
+	}
+	l.Add("k1", 1)
+	l.Add("k2", 2)
+	l.Add("k3", 3)
+	if s := fmt.Sprintf("%v", l); s != "LRU(len=3/cap=5)[k3=3, k2=2, k1=1]" {
+		t.Fatalf("bad string: %s", s)
+	}
+	if s := l.StringN(2); s != "LRU(len=3/cap=5)[k3=3, k2=2, ...]" {
+		t.Fatalf("bad string: %s", s)
+	}
+	if s := l.StringN(0); s != "LRU(len=3/cap=5)[...]" {
+		t.Fatalf("bad string: %s", s)
+	}
+}
+
+func TestLRU_Cap(t *testing.T) {
+	l, err := NewLRU[int, int](5, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if l.Cap() != 5 {
+		t.Fatalf("bad cap: %v", l.Cap())
+	}
+	l.Resize(3)
+	if l.Cap() != 3 {
+		t.Fatalf("bad cap: %v", l.Cap())
+	}
+}
+
+func TestLRU_EvictionCountdown(t *testing.T) {
+	l, err := NewLRU[int, int](4, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Add(3, 3)
+	if adds, ok := l.EvictionCountdown(1); !ok || adds != 2 {
+		t.Fatalf("bad countdown: %v %v", adds, ok)
+	}
+	if adds, ok := l.EvictionCountdown(3); !ok || adds != 4 {
+		t.Fatalf("bad countdown: %v %v", adds, ok)
+	}
+	if _, ok := l.EvictionCountdown(4); ok {
+		t.Fatalf("should not be found")
+	}
+
+	// the countdown matches the actual number of adds
+	adds, _ := l.EvictionCountdown(2)
+	for i := 0; i < adds; i++ {
+		if !l.Contains(2) {
+			t.Fatalf("evicted after %d adds, expected %d", i, adds)
+		}
+		l.Add(10+i, i)
+	}
+	if l.Contains(2) {
+		t.Fatalf("should be evicted after %d adds", adds)
+	}
+}
+
+func TestLRU_PeekVictim(t *testing.T) {
+	l, err := NewLRU[int, int](2, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, 1)
+	if _, _, wouldEvict := l.PeekVictim(2); wouldEvict {
+		t.Fatalf("should not evict when not full")
+	}
+	l.Add(2, 2)
+	if _, _, wouldEvict := l.PeekVictim(1); wouldEvict {
+		t.Fatalf("should not evict when already present")
+	}
+	k, v, wouldEvict := l.PeekVictim(3)
+	if !wouldEvict || k != 1 || v != 1 {
+		t.Fatalf("bad victim: %v %v %v", k, v, wouldEvict)
+	}
+	if keys := l.Keys(); len(keys) != 2 || keys[0] != 1 {
+		t.Fatalf("should not update the cache: %v", keys)
+	}
+	l.Add(3, 3)
+	if l.Contains(k) {
+		t.Fatalf("victim should be evicted")
+	}
+}
+
+func TestNewLRUFromEntries(t *testing.T) {
+	if _, err := NewLRUFromEntries[int, int](0, nil, nil); err == nil {
+		t.Fatalf("should fail with zero size")
+	}
+
+	evictCounter := 0
+	onEvicted := func(k int, v int) {
+		evictCounter++
+	}
+	entries := []Entry[int, int]{{1, 1}, {2, 2}, {3, 3}, {2, 4}, {5, 5}}
+	l, err := NewLRUFromEntries(4, entries, onEvicted)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if keys := l.Keys(); !reflect.DeepEqual(keys, []int{1, 3, 2, 5}) {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	if v, _ := l.Peek(2); v != 4 {
+		t.Fatalf("bad value: %v", v)
+	}
+
+	// oversized input keeps the newest suffix
+	l, err = NewLRUFromEntries(2, entries, onEvicted)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if keys := l.Keys(); !reflect.DeepEqual(keys, []int{2, 5}) {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	if evictCounter != 0 {
+		t.Fatalf("should not evict while constructing: %v", evictCounter)
+	}
+	l.Add(6, 6)
+	if evictCounter != 1 {
+		t.Fatalf("bad evict count: %v", evictCounter)
+	}
+}
+
+func TestLRU_Page(t *testing.T) {
+	l, err := NewLRU[int, int](16, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		l.Add(i, i*10)
+	}
+
+	var keys []int
+	var cursor PageCursor[int]
+	for pages := 0; !cursor.Done(); pages++ {
+		var entries []Entry[int, int]
+		entries, cursor, err = l.Page(cursor, 3)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if len(entries) > 3 {
+			t.Fatalf("bad page: %v", entries)
+		}
+		for _, ent := range entries {
+			if ent.Value != ent.Key*10 {
+				t.Fatalf("bad entry: %v", ent)
+			}
+			keys = append(keys, ent.Key)
+		}
+		if pages > 10 {
+			t.Fatalf("cursor is stuck")
+		}
+	}
+	if !reflect.DeepEqual(keys, []int{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}) {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	if keys := l.Keys(); keys[0] != 0 {
+		t.Fatalf("should not update recent-ness: %v", keys)
+	}
+
+	// the zero key is a cursor like any other
+	l.Add(0, 0)
+	entries, cursor, err := l.Page(PageCursor[int]{}, 1)
+	if err != nil || len(entries) != 1 || entries[0].Key != 0 || cursor.Done() {
+		t.Fatalf("bad page: %v %v %v", entries, cursor, err)
+	}
+	entries, cursor, err = l.Page(cursor, 1)
+	if err != nil || len(entries) != 1 || entries[0].Key != 9 || cursor.Done() {
+		t.Fatalf("bad page: %v %v %v", entries, cursor, err)
+	}
+
+	// a page ending with the oldest entry is the last one
+	entries, cursor, err = l.Page(CursorAfter(2), 1)
+	if err != nil || len(entries) != 1 || entries[0].Key != 1 || !cursor.Done() {
+		t.Fatalf("bad page: %v %v %v", entries, cursor, err)
+	}
+	if entries, _, err := l.Page(cursor, 1); err != nil || len(entries) != 0 {
+		t.Fatalf("bad page: %v %v", entries, err)
+	}
+
+	// a removed cursor is reported
+	if entries, _, err := l.Page(CursorAfter(42), 3); err != ErrCursorNotFound || len(entries) != 0 {
+		t.Fatalf("bad page: %v %v", entries, err)
+	}
+
+	// a non-positive limit returns the rest of the cache
+	if entries, cursor, err := l.Page(CursorAfter(5), 0); err != nil || len(entries) != 4 || entries[0].Key != 4 || !cursor.Done() {
+		t.Fatalf("bad page: %v %v %v", entries, cursor, err)
+	}
+	if entries, cursor, err := l.Page(PageCursor[int]{}, -1); err != nil || len(entries) != l.Len() || !cursor.Done() {
+		t.Fatalf("bad page: %v %v %v", entries, cursor, err)
+	}
+}
+
+func TestLRU_TestFunc(t *testing.T) {
+	l, err := NewLRU[int, int](2, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, 1)
+	l.Add(2, 2)
+	even := func(v int) bool { return v%2 == 0 }
+
+	if present, satisfied := l.TestFunc(2, even); !present || !satisfied {
+		t.Fatalf("bad: %v %v", present, satisfied)
+	}
+	if present, satisfied := l.TestFunc(1, even); !present || satisfied {
+		t.Fatalf("bad: %v %v", present, satisfied)
+	}
+	if present, satisfied := l.TestFunc(3, even); present || satisfied {
+		t.Fatalf("bad: %v %v", present, satisfied)
+	}
+
+	// 1 was not promoted
+	l.Add(3, 3)
+	if l.Contains(1) {
+		t.Fatalf("should not update recent-ness")
+	}
+}
+
+func TestLRU_NewestMatching(t *testing.T) {
+	l, err := NewLRU[int, int](16, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		l.Add(i, i*10)
+	}
+	visited := 0
+	even := func(k, v int) bool {
+		visited++
+		return v%20 == 0
+	}
+
+	entries := l.NewestMatching(3, even)
+	if !reflect.DeepEqual(entries, []Entry[int, int]{{8, 80}, {6, 60}, {4, 40}}) {
+		t.Fatalf("bad entries: %v", entries)
+	}
+	if visited != 6 {
+		t.Fatalf("should stop early: %v", visited)
+	}
+	if entries = l.NewestMatching(10, even); len(entries) != 5 || entries[4].Key != 0 {
+		t.Fatalf("bad entries: %v", entries)
+	}
+	if entries = l.NewestMatching(0, even); len(entries) != 0 {
+		t.Fatalf("bad entries: %v", entries)
+	}
+	if keys := l.Keys(); keys[0] != 0 {
+		t.Fatalf("should not update recent-ness: %v", keys)
+	}
+}
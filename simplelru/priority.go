@@ -0,0 +1,246 @@
+package simplelru
+
+import (
+	"container/list"
+	"errors"
+)
+
+// Priority is the eviction priority of an entry in a PriorityLRU.
+type Priority int
+
+const (
+	// PriorityLow entries are evicted before all others.
+	PriorityLow Priority = iota
+	// PriorityNormal entries are evicted once there are no PriorityLow
+	// entries left.
+	PriorityNormal
+	// PriorityHigh entries are only evicted once all remaining entries are
+	// PriorityHigh.
+	PriorityHigh
+
+	numPriorities = int(PriorityHigh) + 1
+)
+
+// PriorityLRU is a non-thread safe fixed size cache whose entries carry a
+// coarse priority. Eviction always picks an entry of the lowest priority
+// present, and among entries of that priority, the least recently used one:
+// recency only breaks ties within a priority level. This applies to
+// evictions by Add as well as by Resize and RemoveOldest. Get updates the
+// recency of an entry within its own priority level only, and never moves
+// it ahead of lower priority entries in the eviction order.
+type PriorityLRU[Key comparable, Value any] struct {
+	size      int
+	evictList [numPriorities]*list.List
+	items     map[Key]*list.Element
+	onEvict   EvictCallback[Key, Value]
+}
+
+// priorityEntry is used to hold a value in the evictList of its priority
+type priorityEntry[Key, Value any] struct {
+	key      Key
+	value    Value
+	priority Priority
+}
+
+// NewPriorityLRU constructs a PriorityLRU of the given size.
+func NewPriorityLRU[Key comparable, Value any](size int, onEvict EvictCallback[Key, Value]) (*PriorityLRU[Key, Value], error) {
+	if size <= 0 {
+		return nil, errors.New("must provide a positive size")
+	}
+	c := &PriorityLRU[Key, Value]{
+		size:    size,
+		items:   make(map[Key]*list.Element),
+		onEvict: onEvict,
+	}
+	for i := range c.evictList {
+		c.evictList[i] = list.New()
+	}
+	return c, nil
+}
+
+// Purge is used to completely clear the cache.
+func (c *PriorityLRU[Key, Value]) Purge() {
+	for k, v := range c.items {
+		delete(c.items, k)
+		if c.onEvict != nil {
+			c.onEvict(k, v.Value.(*priorityEntry[Key, Value]).value)
+		}
+	}
+	for _, l := range c.evictList {
+		l.Init()
+	}
+}
+
+// Add adds a value to the cache with PriorityNormal. Returns true if an
+// eviction occurred.
+func (c *PriorityLRU[Key, Value]) Add(key Key, value Value) (evicted bool) {
+	return c.AddWithPriority(key, value, PriorityNormal)
+}
+
+// AddWithPriority adds a value to the cache with the given priority, which
+// replaces the priority of an existing entry. Unknown priorities are treated
+// as PriorityNormal. Returns true if an eviction occurred.
+//
+// A new key is not added if the cache is full of entries of higher
+// priority than its own, since it would be the entry to evict: the call
+// returns false, and the eviction callback is not invoked for the value.
+// Use Contains to tell whether the key was added.
+func (c *PriorityLRU[Key, Value]) AddWithPriority(key Key, value Value, priority Priority) (evicted bool) {
+	if priority < PriorityLow || priority > PriorityHigh {
+		priority = PriorityNormal
+	}
+
+	// Check for existing item
+	if ent, ok := c.items[key]; ok {
+		kv := ent.Value.(*priorityEntry[Key, Value])
+		kv.value = value
+		if kv.priority == priority {
+			c.evictList[priority].MoveToFront(ent)
+			return false
+		}
+		c.evictList[kv.priority].Remove(ent)
+		kv.priority = priority
+		c.items[key] = c.evictList[priority].PushFront(kv)
+		return false
+	}
+
+	// Reject an item that would be evicted right away
+	if len(c.items) >= c.size {
+		if oldest := c.oldest(); oldest != nil && oldest.Value.(*priorityEntry[Key, Value]).priority > priority {
+			return false
+		}
+	}
+
+	// Add new item
+	ent := &priorityEntry[Key, Value]{key, value, priority}
+	c.items[key] = c.evictList[priority].PushFront(ent)
+
+	evict := len(c.items) > c.size
+	// Verify size not exceeded
+	if evict {
+		c.removeOldest()
+	}
+	return evict
+}
+
+// Get looks up a key's value from the cache, updating its recent-ness within
+// its priority level.
+func (c *PriorityLRU[Key, Value]) Get(key Key) (value Value, ok bool) {
+	if ent, ok := c.items[key]; ok {
+		kv := ent.Value.(*priorityEntry[Key, Value])
+		c.evictList[kv.priority].MoveToFront(ent)
+		return kv.value, true
+	}
+	return
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (c *PriorityLRU[Key, Value]) Contains(key Key) (ok bool) {
+	_, ok = c.items[key]
+	return ok
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *PriorityLRU[Key, Value]) Peek(key Key) (value Value, ok bool) {
+	if ent, ok := c.items[key]; ok {
+		return ent.Value.(*priorityEntry[Key, Value]).value, true
+	}
+	return
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *PriorityLRU[Key, Value]) Remove(key Key) (present bool) {
+	if ent, ok := c.items[key]; ok {
+		c.removeElement(ent)
+		return true
+	}
+	return false
+}
+
+// RemoveOldest removes the next entry to be evicted from the cache.
+func (c *PriorityLRU[Key, Value]) RemoveOldest() (key Key, value Value, ok bool) {
+	if ent := c.oldest(); ent != nil {
+		c.removeElement(ent)
+		kv := ent.Value.(*priorityEntry[Key, Value])
+		return kv.key, kv.value, true
+	}
+	return
+}
+
+// GetOldest returns the next entry to be evicted.
+func (c *PriorityLRU[Key, Value]) GetOldest() (key Key, value Value, ok bool) {
+	if ent := c.oldest(); ent != nil {
+		kv := ent.Value.(*priorityEntry[Key, Value])
+		return kv.key, kv.value, true
+	}
+	return
+}
+
+// Keys returns a slice of the keys in the cache in eviction order: by
+// increasing priority, and from oldest to newest within a priority.
+func (c *PriorityLRU[Key, Value]) Keys() []Key {
+	keys := make([]Key, 0, len(c.items))
+	for _, l := range c.evictList {
+		for ent := l.Back(); ent != nil; ent = ent.Prev() {
+			keys = append(keys, ent.Value.(*priorityEntry[Key, Value]).key)
+		}
+	}
+	return keys
+}
+
+// Len returns the number of items in the cache.
+func (c *PriorityLRU[Key, Value]) Len() int {
+	return len(c.items)
+}
+
+// PriorityCounts returns the number of entries of each priority.
+func (c *PriorityLRU[Key, Value]) PriorityCounts() map[Priority]int {
+	counts := make(map[Priority]int, numPriorities)
+	for p, l := range c.evictList {
+		counts[Priority(p)] = l.Len()
+	}
+	return counts
+}
+
+// Resize changes the cache size, evicting entries by priority as needed.
+func (c *PriorityLRU[Key, Value]) Resize(size int) (evicted int) {
+	diff := c.Len() - size
+	if diff < 0 {
+		diff = 0
+	}
+	for i := 0; i < diff; i++ {
+		c.removeOldest()
+	}
+	c.size = size
+	return diff
+}
+
+// oldest returns the next element to be evicted, if any.
+func (c *PriorityLRU[Key, Value]) oldest() *list.Element {
+	for _, l := range c.evictList {
+		if ent := l.Back(); ent != nil {
+			return ent
+		}
+	}
+	return nil
+}
+
+// removeOldest removes the next entry to be evicted from the cache.
+func (c *PriorityLRU[Key, Value]) removeOldest() {
+	if ent := c.oldest(); ent != nil {
+		c.removeElement(ent)
+	}
+}
+
+// removeElement is used to remove a given list element from the cache
+func (c *PriorityLRU[Key, Value]) removeElement(e *list.Element) {
+	kv := e.Value.(*priorityEntry[Key, Value])
+	c.evictList[kv.priority].Remove(e)
+	delete(c.items, kv.key)
+	if c.onEvict != nil {
+		c.onEvict(kv.key, kv.value)
+	}
+}
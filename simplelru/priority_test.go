@@ -0,0 +1,132 @@
+package simplelru
+
+import "testing"
+
+func TestPriorityLRU(t *testing.T) {
+	var evicted []int
+	l, err := NewPriorityLRU(4, func(k int, v int) {
+		evicted = append(evicted, k)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.AddWithPriority(1, 1, PriorityHigh)
+	l.AddWithPriority(2, 2, PriorityLow)
+	l.Add(3, 3)
+	l.AddWithPriority(4, 4, PriorityLow)
+	l.Get(2)
+
+	counts := l.PriorityCounts()
+	if counts[PriorityLow] != 2 || counts[PriorityNormal] != 1 || counts[PriorityHigh] != 1 {
+		t.Fatalf("bad counts: %v", counts)
+	}
+	expected := []int{4, 2, 3, 1}
+	for i, k := range l.Keys() {
+		if k != expected[i] {
+			t.Fatalf("bad keys: %v", l.Keys())
+		}
+	}
+
+	// low priority entries go first, oldest first
+	l.Add(5, 5)
+	l.Add(6, 6)
+	if len(evicted) != 2 || evicted[0] != 4 || evicted[1] != 2 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	// then normal ones, even if more recent than high ones
+	l.AddWithPriority(7, 7, PriorityHigh)
+	if evicted[2] != 3 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	// until only high ones are left
+	l.AddWithPriority(8, 8, PriorityHigh)
+	l.AddWithPriority(9, 9, PriorityHigh)
+	if evicted[3] != 5 || evicted[4] != 6 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	l.AddWithPriority(10, 10, PriorityHigh)
+	if evicted[5] != 1 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+}
+
+func TestPriorityLRU_ChangePriority(t *testing.T) {
+	l, err := NewPriorityLRU[int, int](2, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.AddWithPriority(1, 1, PriorityLow)
+	l.Add(2, 2)
+	l.AddWithPriority(1, 10, PriorityHigh)
+	if k, _, _ := l.GetOldest(); k != 2 {
+		t.Fatalf("bad oldest: %v", k)
+	}
+	if v, _ := l.Peek(1); v != 10 {
+		t.Fatalf("bad value: %v", v)
+	}
+	if counts := l.PriorityCounts(); counts[PriorityLow] != 0 || counts[PriorityHigh] != 1 {
+		t.Fatalf("bad counts: %v", counts)
+	}
+}
+
+func TestPriorityLRU_Resize(t *testing.T) {
+	l, err := NewPriorityLRU[int, int](4, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.AddWithPriority(1, 1, PriorityHigh)
+	l.Add(2, 2)
+	l.AddWithPriority(3, 3, PriorityLow)
+	l.AddWithPriority(4, 4, PriorityHigh)
+
+	if n := l.Resize(2); n != 2 {
+		t.Fatalf("bad resize: %v", n)
+	}
+	if !l.Contains(1) || !l.Contains(4) {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+	if k, _, ok := l.RemoveOldest(); !ok || k != 1 {
+		t.Fatalf("bad oldest: %v", k)
+	}
+	if !l.Remove(4) || l.Len() != 0 {
+		t.Fatalf("should be empty")
+	}
+	if _, _, ok := l.RemoveOldest(); ok {
+		t.Fatalf("should be empty")
+	}
+}
+
+func TestPriorityLRU_RejectLower(t *testing.T) {
+	var evicted []int
+	l, err := NewPriorityLRU(2, func(k int, v int) {
+		evicted = append(evicted, k)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.AddWithPriority(1, 1, PriorityHigh)
+	l.Add(2, 2)
+
+	// a low priority key would evict itself, so it is not added
+	if l.AddWithPriority(3, 3, PriorityLow) {
+		t.Fatalf("should not evict")
+	}
+	if l.Contains(3) || l.Len() != 2 || len(evicted) != 0 {
+		t.Fatalf("bad: %v %v", l.Keys(), evicted)
+	}
+
+	// a key of the lowest priority present evicts the oldest of them
+	if !l.Add(4, 4) {
+		t.Fatalf("should evict")
+	}
+	if !l.Contains(4) || len(evicted) != 1 || evicted[0] != 2 {
+		t.Fatalf("bad: %v %v", l.Keys(), evicted)
+	}
+
+	// lowering the priority of an existing key is not rejected
+	l.AddWithPriority(1, 1, PriorityLow)
+	if v, ok := l.Peek(1); !ok || v != 1 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+}
@@ -0,0 +1,114 @@
+package simplelru
+
+// SymbolLRU is a non-thread safe fixed size LRU cache that assigns each key a
+// compact uint64 ID when it is first added, so that entries can be referred
+// to by ID elsewhere. The ID is released when the entry leaves the cache and
+// is never reused: adding the key again assigns it a new ID, and a stale ID
+// simply no longer resolves. IDs start at 1, so 0 is never a valid ID.
+type SymbolLRU[Key comparable, Value any] struct {
+	lru     *LRU[Key, Value]
+	ids     map[Key]uint64
+	keys    map[uint64]Key
+	lastID  uint64
+	onEvict EvictCallback[Key, Value]
+}
+
+// NewSymbolLRU constructs a SymbolLRU of the given size.
+func NewSymbolLRU[Key comparable, Value any](size int, onEvict EvictCallback[Key, Value]) (*SymbolLRU[Key, Value], error) {
+	c := &SymbolLRU[Key, Value]{
+		ids:     make(map[Key]uint64),
+		keys:    make(map[uint64]Key),
+		onEvict: onEvict,
+	}
+	lru, err := NewLRU(size, c.release)
+	if err != nil {
+		return nil, err
+	}
+	c.lru = lru
+	return c, nil
+}
+
+// release drops the ID of an entry leaving the cache.
+func (c *SymbolLRU[Key, Value]) release(key Key, value Value) {
+	delete(c.keys, c.ids[key])
+	delete(c.ids, key)
+	if c.onEvict != nil {
+		c.onEvict(key, value)
+	}
+}
+
+// Add adds a value to the cache, assigning the key an ID if it is new.
+// Returns true if an eviction occurred.
+func (c *SymbolLRU[Key, Value]) Add(key Key, value Value) (evicted bool) {
+	if _, ok := c.ids[key]; !ok {
+		c.lastID++
+		c.ids[key] = c.lastID
+		c.keys[c.lastID] = key
+	}
+	return c.lru.Add(key, value)
+}
+
+// IDOf returns the ID of a key in the cache.
+func (c *SymbolLRU[Key, Value]) IDOf(key Key) (id uint64, ok bool) {
+	id, ok = c.ids[key]
+	return
+}
+
+// KeyOf returns the key with the given ID, if it is still in the cache.
+func (c *SymbolLRU[Key, Value]) KeyOf(id uint64) (key Key, ok bool) {
+	key, ok = c.keys[id]
+	return
+}
+
+// Get looks up a key's value from the cache.
+func (c *SymbolLRU[Key, Value]) Get(key Key) (value Value, ok bool) {
+	return c.lru.Get(key)
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (c *SymbolLRU[Key, Value]) Contains(key Key) (ok bool) {
+	return c.lru.Contains(key)
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *SymbolLRU[Key, Value]) Peek(key Key) (value Value, ok bool) {
+	return c.lru.Peek(key)
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *SymbolLRU[Key, Value]) Remove(key Key) (present bool) {
+	return c.lru.Remove(key)
+}
+
+// RemoveOldest removes the oldest item from the cache.
+func (c *SymbolLRU[Key, Value]) RemoveOldest() (key Key, value Value, ok bool) {
+	return c.lru.RemoveOldest()
+}
+
+// GetOldest returns the oldest entry
+func (c *SymbolLRU[Key, Value]) GetOldest() (key Key, value Value, ok bool) {
+	return c.lru.GetOldest()
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *SymbolLRU[Key, Value]) Keys() []Key {
+	return c.lru.Keys()
+}
+
+// Len returns the number of items in the cache.
+func (c *SymbolLRU[Key, Value]) Len() int {
+	return c.lru.Len()
+}
+
+// Purge is used to completely clear the cache.
+func (c *SymbolLRU[Key, Value]) Purge() {
+	c.lru.Purge()
+}
+
+// Resize changes the cache size.
+func (c *SymbolLRU[Key, Value]) Resize(size int) (evicted int) {
+	return c.lru.Resize(size)
+}
@@ -0,0 +1,77 @@
+package simplelru
+
+import "testing"
+
+// checkSymbols verifies that IDs and keys map to each other for exactly
+// the keys in the cache.
+func checkSymbols(t *testing.T, l *SymbolLRU[string, int]) {
+	t.Helper()
+	if len(l.ids) != l.Len() || len(l.keys) != l.Len() {
+		t.Fatalf("bad mapping sizes: %v %v %v", len(l.ids), len(l.keys), l.Len())
+	}
+	for _, k := range l.Keys() {
+		id, ok := l.IDOf(k)
+		if !ok {
+			t.Fatalf("missing id for %v", k)
+		}
+		if k2, ok := l.KeyOf(id); !ok || k2 != k {
+			t.Fatalf("bad key for id %v: %v", id, k2)
+		}
+	}
+}
+
+func TestSymbolLRU(t *testing.T) {
+	evictCounter := 0
+	l, err := NewSymbolLRU(2, func(k string, v int) {
+		evictCounter++
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add("a", 1)
+	l.Add("b", 2)
+	idA, _ := l.IDOf("a")
+	idB, _ := l.IDOf("b")
+	if idA == 0 || idB == 0 || idA == idB {
+		t.Fatalf("bad ids: %v %v", idA, idB)
+	}
+
+	// updates keep the id
+	l.Add("a", 10)
+	if id, _ := l.IDOf("a"); id != idA {
+		t.Fatalf("id should be stable: %v", id)
+	}
+	checkSymbols(t, l)
+
+	l.Add("c", 3) // evicts b
+	if _, ok := l.IDOf("b"); ok {
+		t.Fatalf("id should be released")
+	}
+	if _, ok := l.KeyOf(idB); ok {
+		t.Fatalf("id should be released")
+	}
+	checkSymbols(t, l)
+
+	// ids are not reused
+	l.Add("b", 2) // evicts a
+	if id, _ := l.IDOf("b"); id == idB || id == idA {
+		t.Fatalf("id should not be reused: %v", id)
+	}
+	if _, ok := l.KeyOf(idA); ok {
+		t.Fatalf("id should be released")
+	}
+	checkSymbols(t, l)
+
+	l.Remove("c")
+	checkSymbols(t, l)
+	l.Add("d", 4)
+	l.Resize(1)
+	checkSymbols(t, l)
+	l.Purge()
+	checkSymbols(t, l)
+
+	if evictCounter != 5 {
+		t.Fatalf("bad evict count: %v", evictCounter)
+	}
+}
@@ -0,0 +1,152 @@
+package simplelru
+
+// TaggedLRU is a non-thread safe fixed size LRU cache whose entries can
+// carry any number of string tags, so that all entries sharing a tag can be
+// invalidated at once, like surrogate keys in a CDN. The tag index is kept
+// consistent whenever an entry leaves the cache, whether by eviction,
+// Remove, RemoveOldest, Resize, Purge or InvalidateTag.
+type TaggedLRU[Key comparable, Value any] struct {
+	lru     *LRU[Key, Value]
+	tags    map[Key][]string
+	tagged  map[string]map[Key]struct{}
+	onEvict EvictCallback[Key, Value]
+}
+
+// NewTaggedLRU constructs a TaggedLRU of the given size.
+func NewTaggedLRU[Key comparable, Value any](size int, onEvict EvictCallback[Key, Value]) (*TaggedLRU[Key, Value], error) {
+	c := &TaggedLRU[Key, Value]{
+		tags:    make(map[Key][]string),
+		tagged:  make(map[string]map[Key]struct{}),
+		onEvict: onEvict,
+	}
+	lru, err := NewLRU(size, c.evicted)
+	if err != nil {
+		return nil, err
+	}
+	c.lru = lru
+	return c, nil
+}
+
+// evicted unindexes an entry leaving the cache.
+func (c *TaggedLRU[Key, Value]) evicted(key Key, value Value) {
+	c.untag(key)
+	if c.onEvict != nil {
+		c.onEvict(key, value)
+	}
+}
+
+// untag drops all tags of the key from the index.
+func (c *TaggedLRU[Key, Value]) untag(key Key) {
+	for _, tag := range c.tags[key] {
+		keys := c.tagged[tag]
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(c.tagged, tag)
+		}
+	}
+	delete(c.tags, key)
+}
+
+// Add adds a value to the cache without tags, dropping the tags of an
+// existing entry. Returns true if an eviction occurred.
+func (c *TaggedLRU[Key, Value]) Add(key Key, value Value) (evicted bool) {
+	return c.AddWithTags(key, value)
+}
+
+// AddWithTags adds a value to the cache with the given tags, which replace
+// the tags of an existing entry. Returns true if an eviction occurred.
+func (c *TaggedLRU[Key, Value]) AddWithTags(key Key, value Value, tags ...string) (evicted bool) {
+	c.untag(key)
+	evicted = c.lru.Add(key, value)
+	for _, tag := range tags {
+		keys, ok := c.tagged[tag]
+		if !ok {
+			keys = make(map[Key]struct{})
+			c.tagged[tag] = keys
+		}
+		if _, dup := keys[key]; dup {
+			continue
+		}
+		keys[key] = struct{}{}
+		c.tags[key] = append(c.tags[key], tag)
+	}
+	return evicted
+}
+
+// InvalidateTag removes all entries carrying the tag, invoking the eviction
+// callback for each, and returns the number of entries removed.
+func (c *TaggedLRU[Key, Value]) InvalidateTag(tag string) (removed int) {
+	keys := make([]Key, 0, len(c.tagged[tag]))
+	for key := range c.tagged[tag] {
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		if c.lru.Remove(key) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// TagsOf returns the tags of the key, in the order they were added, or nil
+// if the key is not in the cache or has no tags.
+func (c *TaggedLRU[Key, Value]) TagsOf(key Key) []string {
+	tags := c.tags[key]
+	if len(tags) == 0 {
+		return nil
+	}
+	return append([]string(nil), tags...)
+}
+
+// Get looks up a key's value from the cache.
+func (c *TaggedLRU[Key, Value]) Get(key Key) (value Value, ok bool) {
+	return c.lru.Get(key)
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (c *TaggedLRU[Key, Value]) Contains(key Key) (ok bool) {
+	return c.lru.Contains(key)
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *TaggedLRU[Key, Value]) Peek(key Key) (value Value, ok bool) {
+	return c.lru.Peek(key)
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *TaggedLRU[Key, Value]) Remove(key Key) (present bool) {
+	return c.lru.Remove(key)
+}
+
+// RemoveOldest removes the oldest item from the cache.
+func (c *TaggedLRU[Key, Value]) RemoveOldest() (key Key, value Value, ok bool) {
+	return c.lru.RemoveOldest()
+}
+
+// GetOldest returns the oldest entry
+func (c *TaggedLRU[Key, Value]) GetOldest() (key Key, value Value, ok bool) {
+	return c.lru.GetOldest()
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *TaggedLRU[Key, Value]) Keys() []Key {
+	return c.lru.Keys()
+}
+
+// Len returns the number of items in the cache.
+func (c *TaggedLRU[Key, Value]) Len() int {
+	return c.lru.Len()
+}
+
+// Purge is used to completely clear the cache.
+func (c *TaggedLRU[Key, Value]) Purge() {
+	c.lru.Purge()
+}
+
+// Resize changes the cache size.
+func (c *TaggedLRU[Key, Value]) Resize(size int) (evicted int) {
+	return c.lru.Resize(size)
+}
@@ -0,0 +1,101 @@
+package simplelru
+
+import "testing"
+
+// checkTagIndex verifies that both directions of the tag index agree with
+// each other and only reference keys in the cache.
+func checkTagIndex(t *testing.T, l *TaggedLRU[int, int]) {
+	t.Helper()
+	n := 0
+	for key, tags := range l.tags {
+		if !l.Contains(key) {
+			t.Fatalf("tags for absent key %v", key)
+		}
+		for _, tag := range tags {
+			if _, ok := l.tagged[tag][key]; !ok {
+				t.Fatalf("key %v missing from tag %v", key, tag)
+			}
+			n++
+		}
+	}
+	for tag, keys := range l.tagged {
+		if len(keys) == 0 {
+			t.Fatalf("empty tag %v", tag)
+		}
+		n -= len(keys)
+	}
+	if n != 0 {
+		t.Fatalf("inconsistent tag index")
+	}
+}
+
+func TestTaggedLRU(t *testing.T) {
+	var evicted []int
+	l, err := NewTaggedLRU(4, func(k int, v int) {
+		evicted = append(evicted, k)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.AddWithTags(1, 1, "odd", "all")
+	l.AddWithTags(2, 2, "even", "all", "all")
+	l.AddWithTags(3, 3, "odd", "all")
+	l.Add(4, 4)
+	checkTagIndex(t, l)
+
+	if tags := l.TagsOf(2); len(tags) != 2 || tags[0] != "even" || tags[1] != "all" {
+		t.Fatalf("bad tags: %v", tags)
+	}
+	if tags := l.TagsOf(4); tags != nil {
+		t.Fatalf("bad tags: %v", tags)
+	}
+
+	if n := l.InvalidateTag("odd"); n != 2 {
+		t.Fatalf("bad invalidated count: %v", n)
+	}
+	if l.Contains(1) || l.Contains(3) || len(evicted) != 2 {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+	checkTagIndex(t, l)
+	if n := l.InvalidateTag("odd"); n != 0 {
+		t.Fatalf("bad invalidated count: %v", n)
+	}
+
+	// replacing tags
+	l.AddWithTags(2, 20, "new")
+	if n := l.InvalidateTag("even"); n != 0 {
+		t.Fatalf("bad invalidated count: %v", n)
+	}
+	checkTagIndex(t, l)
+}
+
+func TestTaggedLRU_Evictions(t *testing.T) {
+	l, err := NewTaggedLRU[int, int](3, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		l.AddWithTags(i, i, "all", string(rune('a'+i%3)))
+		checkTagIndex(t, l)
+	}
+	if n := l.InvalidateTag("all"); n != 3 {
+		t.Fatalf("bad invalidated count: %v", n)
+	}
+
+	for i := 0; i < 3; i++ {
+		l.AddWithTags(i, i, "x")
+	}
+	l.Remove(0)
+	checkTagIndex(t, l)
+	l.RemoveOldest()
+	checkTagIndex(t, l)
+	l.AddWithTags(3, 3, "x")
+	l.Resize(1)
+	checkTagIndex(t, l)
+	l.Purge()
+	checkTagIndex(t, l)
+	if len(l.tags) != 0 || len(l.tagged) != 0 {
+		t.Fatalf("index should be empty")
+	}
+}
@@ -0,0 +1,37 @@
+package simplelru
+
+// TraceableLRU is an LRU that records the key of every entry that left the
+// cache, in the order the eviction callback saw them. It is intended for
+// offline verification of eviction decisions, e.g. diffing the eviction
+// sequence for an operation trace against another implementation, and is
+// not meant for production use as the record grows without bound.
+type TraceableLRU[Key comparable, Value any] struct {
+	*LRU[Key, Value]
+	evictedKeys []Key
+}
+
+// NewTraceableLRU constructs a TraceableLRU of the given size. The optional
+// onEvict callback is invoked after the evicted key has been recorded.
+func NewTraceableLRU[Key comparable, Value any](size int, onEvict EvictCallback[Key, Value]) (*TraceableLRU[Key, Value], error) {
+	c := &TraceableLRU[Key, Value]{}
+	lru, err := NewLRU(size, func(key Key, value Value) {
+		c.evictedKeys = append(c.evictedKeys, key)
+		if onEvict != nil {
+			onEvict(key, value)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.LRU = lru
+	return c, nil
+}
+
+// EvictedKeys returns a copy of the keys evicted since construction, from
+// first to last. Like the eviction callback, this includes entries removed
+// by Remove, RemoveOldest, Resize and Purge.
+func (c *TraceableLRU[Key, Value]) EvictedKeys() []Key {
+	keys := make([]Key, len(c.evictedKeys))
+	copy(keys, c.evictedKeys)
+	return keys
+}
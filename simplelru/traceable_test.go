@@ -0,0 +1,47 @@
+package simplelru
+
+import "testing"
+
+func TestTraceableLRU(t *testing.T) {
+	evictCounter := 0
+	l, err := NewTraceableLRU(2, func(k int, v int) {
+		evictCounter++
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Add(3, 3) // evicts 1
+	l.Get(2)
+	l.Add(4, 4) // evicts 3
+	l.Remove(2)
+	l.Resize(1)
+	l.Add(5, 5) // evicts 4
+
+	expected := []int{1, 3, 2, 4}
+	keys := l.EvictedKeys()
+	if len(keys) != len(expected) {
+		t.Fatalf("bad evicted keys: %v", keys)
+	}
+	for i, k := range keys {
+		if k != expected[i] {
+			t.Fatalf("bad evicted keys: %v", keys)
+		}
+	}
+	if evictCounter != len(expected) {
+		t.Fatalf("bad evict count: %v", evictCounter)
+	}
+
+	keys[0] = 42
+	if l.EvictedKeys()[0] != 1 {
+		t.Fatalf("should return a copy")
+	}
+}
+
+func TestTraceableLRU_Invalid(t *testing.T) {
+	if _, err := NewTraceableLRU[int, int](0, nil); err == nil {
+		t.Fatalf("should fail with invalid size")
+	}
+}
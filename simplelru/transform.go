@@ -0,0 +1,20 @@
+package simplelru
+
+// Transform builds an LRU of the given size holding the entries of src with
+// their keys and values mapped by keyMap and valMap, in the same recency
+// order. The source cache is left unchanged, and the recent-ness of its
+// entries is not updated. If two keys of src map to the same key, the newer
+// entry wins, taking both its value and its position. If there are more
+// entries than fit, only the newest are kept. The new cache has no eviction
+// callback.
+func Transform[K1 comparable, V1 any, K2 comparable, V2 any](src *LRU[K1, V1], size int, keyMap func(K1) K2, valMap func(V1) V2) (*LRU[K2, V2], error) {
+	dst, err := NewLRU[K2, V2](size, nil)
+	if err != nil {
+		return nil, err
+	}
+	for ent := src.evictList.Back(); ent != nil; ent = ent.Prev() {
+		kv := ent.Value.(*entry[K1, V1])
+		dst.Add(keyMap(kv.key), valMap(kv.value))
+	}
+	return dst, nil
+}
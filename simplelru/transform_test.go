@@ -0,0 +1,64 @@
+package simplelru
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestTransform(t *testing.T) {
+	src, err := NewLRU[int, int](8, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		src.Add(i, i*10)
+	}
+	src.Get(0)
+
+	dst, err := Transform(src, 8, strconv.Itoa, func(v int) string {
+		return strconv.Itoa(v + 1)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if keys := dst.Keys(); !reflect.DeepEqual(keys, []string{"1", "2", "3", "4", "0"}) {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	if v, _ := dst.Peek("3"); v != "31" {
+		t.Fatalf("bad value: %v", v)
+	}
+	if keys := src.Keys(); !reflect.DeepEqual(keys, []int{1, 2, 3, 4, 0}) {
+		t.Fatalf("source should be unchanged: %v", keys)
+	}
+
+	// 1 and 3 collide, and the newer 3 wins
+	odd := func(k int) int { return k % 2 }
+	ident := func(v int) int { return v }
+	col, err := Transform(src, 8, odd, ident)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if keys := col.Keys(); !reflect.DeepEqual(keys, []int{1, 0}) {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	if v, _ := col.Peek(1); v != 30 {
+		t.Fatalf("bad value: %v", v)
+	}
+	if v, _ := col.Peek(0); v != 0 {
+		t.Fatalf("bad value: %v", v)
+	}
+
+	// oversized sources keep the newest entries
+	small, err := Transform(src, 2, ident, ident)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if keys := small.Keys(); !reflect.DeepEqual(keys, []int{4, 0}) {
+		t.Fatalf("bad keys: %v", keys)
+	}
+
+	if _, err := Transform(src, 0, ident, ident); err == nil {
+		t.Fatalf("should fail with zero size")
+	}
+}
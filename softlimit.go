@@ -0,0 +1,216 @@
+package lru
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/errorhandler/golang-lru/simplelru"
+)
+
+// softLimitShrinkBatch is the number of entries the shrinker evicts per lock
+// acquisition, so that a large trim does not stall other callers.
+const softLimitShrinkBatch = 16
+
+// SoftLimitLRU is a thread-safe LRU cache with a soft and a hard size limit.
+// Once an Add grows the cache past the soft limit, a background goroutine
+// trims it back down to the soft limit, taking the eviction cost off the Add
+// path and spreading bursts of evictions out over time. Add only evicts
+// synchronously when the cache is at the hard limit.
+type SoftLimitLRU[Key comparable, Value any] struct {
+	lru         *simplelru.LRU[Key, Value]
+	softSize    int
+	evictedKeys []Key
+	evictedVals []Value
+	onEvictedCB func(k Key, v Value)
+	lock        sync.RWMutex
+
+	shrinkCh  chan struct{}
+	closeCh   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewSoftLimitLRU creates a SoftLimitLRU with the given limits and optional
+// eviction callback, and starts its background shrinker. Close must be
+// called to stop the shrinker once the cache is no longer used.
+func NewSoftLimitLRU[Key comparable, Value any](softSize, hardSize int, onEvict simplelru.EvictCallback[Key, Value]) (*SoftLimitLRU[Key, Value], error) {
+	c, err := newSoftLimitLRU(softSize, hardSize, onEvict)
+	if err != nil {
+		return nil, err
+	}
+	c.wg.Add(1)
+	go c.shrinker()
+	return c, nil
+}
+
+// newSoftLimitLRU creates a SoftLimitLRU without starting the shrinker.
+func newSoftLimitLRU[Key comparable, Value any](softSize, hardSize int, onEvict simplelru.EvictCallback[Key, Value]) (*SoftLimitLRU[Key, Value], error) {
+	if softSize <= 0 {
+		return nil, fmt.Errorf("invalid soft size")
+	}
+	if hardSize < softSize {
+		return nil, fmt.Errorf("invalid hard size")
+	}
+	c := &SoftLimitLRU[Key, Value]{
+		softSize:    softSize,
+		onEvictedCB: onEvict,
+		shrinkCh:    make(chan struct{}, 1),
+		closeCh:     make(chan struct{}),
+	}
+	lru, err := simplelru.NewLRU(hardSize, c.onEvicted)
+	if err != nil {
+		return nil, err
+	}
+	c.lru = lru
+	return c, nil
+}
+
+// onEvicted saves evicted key/val to be sent to the externally registered
+// callback outside of critical section
+func (c *SoftLimitLRU[Key, Value]) onEvicted(k Key, v Value) {
+	if c.onEvictedCB != nil {
+		c.evictedKeys = append(c.evictedKeys, k)
+		c.evictedVals = append(c.evictedVals, v)
+	}
+}
+
+// takeEvicted returns and resets the saved evictions. The caller must hold
+// the lock.
+func (c *SoftLimitLRU[Key, Value]) takeEvicted() (ks []Key, vs []Value) {
+	ks, vs = c.evictedKeys, c.evictedVals
+	c.evictedKeys, c.evictedVals = nil, nil
+	return
+}
+
+// notifyEvicted invokes the eviction callback, outside of critical section.
+func (c *SoftLimitLRU[Key, Value]) notifyEvicted(ks []Key, vs []Value) {
+	for i := 0; i < len(ks); i++ {
+		c.onEvictedCB(ks[i], vs[i])
+	}
+}
+
+// shrinker trims the cache each time it is signalled, until closed.
+func (c *SoftLimitLRU[Key, Value]) shrinker() {
+	defer c.wg.Done()
+	for {
+		select {
+		case <-c.closeCh:
+			return
+		case <-c.shrinkCh:
+			c.Shrink()
+		}
+	}
+}
+
+// Shrink evicts the oldest entries until the cache is within its soft limit,
+// returning the number evicted. This is normally done by the background
+// shrinker, but it can be called directly to trim synchronously.
+func (c *SoftLimitLRU[Key, Value]) Shrink() (evicted int) {
+	for {
+		c.lock.Lock()
+		n := c.lru.Len() - c.softSize
+		if n > softLimitShrinkBatch {
+			n = softLimitShrinkBatch
+		}
+		for i := 0; i < n; i++ {
+			c.lru.RemoveOldest()
+		}
+		ks, vs := c.takeEvicted()
+		c.lock.Unlock()
+		c.notifyEvicted(ks, vs)
+		if n <= 0 {
+			return
+		}
+		evicted += n
+	}
+}
+
+// Close stops the background shrinker. The cache remains usable afterwards,
+// but is then only bounded by its hard limit. Close may be called multiple
+// times.
+func (c *SoftLimitLRU[Key, Value]) Close() {
+	c.closeOnce.Do(func() {
+		close(c.closeCh)
+	})
+	c.wg.Wait()
+}
+
+// Add adds a value to the cache, signalling the shrinker if the cache is
+// over its soft limit. Returns true if an eviction occurred, which only
+// happens at the hard limit.
+func (c *SoftLimitLRU[Key, Value]) Add(key Key, value Value) (evicted bool) {
+	c.lock.Lock()
+	evicted = c.lru.Add(key, value)
+	overSoftLimit := c.lru.Len() > c.softSize
+	ks, vs := c.takeEvicted()
+	c.lock.Unlock()
+	if overSoftLimit {
+		select {
+		case c.shrinkCh <- struct{}{}:
+		default:
+		}
+	}
+	c.notifyEvicted(ks, vs)
+	return
+}
+
+// Get looks up a key's value from the cache.
+func (c *SoftLimitLRU[Key, Value]) Get(key Key) (value Value, ok bool) {
+	c.lock.Lock()
+	value, ok = c.lru.Get(key)
+	c.lock.Unlock()
+	return value, ok
+}
+
+// Contains checks if a key is in the cache, without updating the
+// recent-ness or deleting it for being stale.
+func (c *SoftLimitLRU[Key, Value]) Contains(key Key) bool {
+	c.lock.RLock()
+	containKey := c.lru.Contains(key)
+	c.lock.RUnlock()
+	return containKey
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *SoftLimitLRU[Key, Value]) Peek(key Key) (value Value, ok bool) {
+	c.lock.RLock()
+	value, ok = c.lru.Peek(key)
+	c.lock.RUnlock()
+	return value, ok
+}
+
+// Remove removes the provided key from the cache.
+func (c *SoftLimitLRU[Key, Value]) Remove(key Key) (present bool) {
+	c.lock.Lock()
+	present = c.lru.Remove(key)
+	ks, vs := c.takeEvicted()
+	c.lock.Unlock()
+	c.notifyEvicted(ks, vs)
+	return
+}
+
+// Purge is used to completely clear the cache.
+func (c *SoftLimitLRU[Key, Value]) Purge() {
+	c.lock.Lock()
+	c.lru.Purge()
+	ks, vs := c.takeEvicted()
+	c.lock.Unlock()
+	c.notifyEvicted(ks, vs)
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *SoftLimitLRU[Key, Value]) Keys() []Key {
+	c.lock.RLock()
+	keys := c.lru.Keys()
+	c.lock.RUnlock()
+	return keys
+}
+
+// Len returns the number of items in the cache.
+func (c *SoftLimitLRU[Key, Value]) Len() int {
+	c.lock.RLock()
+	length := c.lru.Len()
+	c.lock.RUnlock()
+	return length
+}
@@ -0,0 +1,85 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSoftLimitLRU_Invalid(t *testing.T) {
+	if _, err := NewSoftLimitLRU[int, int](0, 4, nil); err == nil {
+		t.Fatalf("should fail with invalid soft size")
+	}
+	if _, err := NewSoftLimitLRU[int, int](4, 2, nil); err == nil {
+		t.Fatalf("should fail with invalid hard size")
+	}
+}
+
+func TestSoftLimitLRU_Shrink(t *testing.T) {
+	var evicted []int
+	// no background shrinker, so trimming only happens when asked to
+	l, err := newSoftLimitLRU(4, 8, func(k int, v int) {
+		evicted = append(evicted, k)
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for i := 0; i < 8; i++ {
+		if l.Add(i, i) {
+			t.Fatalf("should not evict below hard limit")
+		}
+	}
+	if l.Len() != 8 || len(evicted) != 0 {
+		t.Fatalf("bad len: %v evicted: %v", l.Len(), evicted)
+	}
+
+	if !l.Add(8, 8) {
+		t.Fatalf("should evict at hard limit")
+	}
+	if l.Len() != 8 || len(evicted) != 1 || evicted[0] != 0 {
+		t.Fatalf("bad len: %v evicted: %v", l.Len(), evicted)
+	}
+
+	if n := l.Shrink(); n != 4 {
+		t.Fatalf("bad shrink count: %v", n)
+	}
+	if l.Len() != 4 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+	for i, k := range l.Keys() {
+		if k != i+5 {
+			t.Fatalf("bad keys: %v", l.Keys())
+		}
+	}
+	if n := l.Shrink(); n != 0 {
+		t.Fatalf("bad shrink count: %v", n)
+	}
+}
+
+func TestSoftLimitLRU_Shrinker(t *testing.T) {
+	evictCh := make(chan int, 64)
+	l, err := NewSoftLimitLRU(2, 64, func(k int, v int) {
+		evictCh <- k
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer l.Close()
+
+	for i := 0; i < 40; i++ {
+		l.Add(i, i)
+	}
+	for i := 0; i < 38; i++ {
+		select {
+		case <-evictCh:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("shrinker did not run, len: %v", l.Len())
+		}
+	}
+	if l.Len() != 2 || !l.Contains(38) || !l.Contains(39) {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+
+	l.Close()
+	l.Close()
+}
@@ -0,0 +1,41 @@
+package lru
+
+import "sync/atomic"
+
+// Stats is a snapshot of the size and running counts of a cache.
+type Stats struct {
+	// Len and Cap are the number of entries in the cache and its capacity.
+	Len, Cap int
+	// Hits and Misses count the lookups of Get and the other methods that
+	// count as an access to the key, but not those of Peek and Contains.
+	Hits, Misses uint64
+	// Evictions counts the entries evicted to make room, like
+	// TakeEvictionCount, but is never reset.
+	Evictions uint64
+}
+
+// HitRatio returns the fraction of lookups that were hits, or 0 if there
+// were none.
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// Stats returns the size and running counts of the cache. The counts are
+// read without the lock, so they may be off by the operations running
+// concurrently.
+func (c *Cache[Key, Value]) Stats() Stats {
+	c.endWarmup()
+	c.lock.RLock()
+	s := Stats{Len: c.lru.Len(), Cap: c.lru.Cap()}
+	if c.warmingUp() {
+		s.Cap = c.warmup.size
+	}
+	c.lock.RUnlock()
+	s.Hits = atomic.LoadUint64(&c.hits)
+	s.Misses = atomic.LoadUint64(&c.misses)
+	s.Evictions = atomic.LoadUint64(&c.totalEvictions)
+	return s
+}
@@ -0,0 +1,34 @@
+package lru
+
+import "testing"
+
+func TestCacheStats(t *testing.T) {
+	l, err := New[int, int](2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if r := l.Stats().HitRatio(); r != 0 {
+		t.Fatalf("bad hit ratio: %v", r)
+	}
+
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Add(3, 3)
+	l.Get(3)
+	l.Get(2)
+	l.Get(1)
+	l.Peek(2)
+	l.TakeEvictionCount()
+	l.Resize(1)
+
+	s := l.Stats()
+	if s.Len != 1 || s.Cap != 1 {
+		t.Fatalf("bad size: %v", s)
+	}
+	if s.Hits != 2 || s.Misses != 1 || s.Evictions != 2 {
+		t.Fatalf("bad counts: %v", s)
+	}
+	if r := s.HitRatio(); r < 0.66 || r > 0.67 {
+		t.Fatalf("bad hit ratio: %v", r)
+	}
+}
@@ -0,0 +1,64 @@
+package lru
+
+import (
+	"context"
+
+	"github.com/errorhandler/golang-lru/simplelru"
+)
+
+// streamBatchSize is the number of entries Stream copies under each read
+// lock.
+const streamBatchSize = 64
+
+// Stream sends the entries of the cache on the returned channel, from the
+// newest to the oldest, without updating their recent-ness, and closes the
+// channel once all entries are sent or ctx is done.
+//
+// The entries are read in batches with Page, taking the read lock once per
+// batch rather than for the whole stream, so the stream is not a consistent
+// snapshot: entries added, promoted or removed while it runs can be skipped
+// or sent twice. If the last entry of a batch is removed or evicted before
+// the next batch is read, the stream resumes after the oldest entry of the
+// batch still in the cache, or from the newest entry of the cache if none
+// is left. Pending entries reserved with AddPending are skipped.
+//
+// The goroutine feeding the channel runs until the channel is drained or
+// ctx is cancelled, so callers that stop reading early must cancel ctx to
+// avoid leaking it.
+func (c *Cache[Key, Value]) Stream(ctx context.Context) <-chan simplelru.Entry[Key, Value] {
+	ch := make(chan simplelru.Entry[Key, Value])
+	go func() {
+		defer close(ch)
+		var cursor simplelru.PageCursor[Key]
+		var batch []simplelru.Entry[Key, Value]
+		for !cursor.Done() {
+			entries, next, err := c.Page(cursor, streamBatchSize)
+			if err != nil {
+				cursor, batch = c.resumeCursor(batch)
+				continue
+			}
+			cursor, batch = next, entries
+			for _, ent := range entries {
+				select {
+				case ch <- ent:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+// resumeCursor returns a cursor after the last entry of batch still in the
+// cache, or the zero cursor if none is left, along with the rest of batch.
+func (c *Cache[Key, Value]) resumeCursor(batch []simplelru.Entry[Key, Value]) (simplelru.PageCursor[Key], []simplelru.Entry[Key, Value]) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for i := len(batch) - 1; i >= 0; i-- {
+		if c.lru.Contains(batch[i].Key) {
+			return simplelru.CursorAfter(batch[i].Key), batch[:i+1]
+		}
+	}
+	return simplelru.PageCursor[Key]{}, nil
+}
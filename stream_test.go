@@ -0,0 +1,80 @@
+package lru
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCacheStream(t *testing.T) {
+	l, err := New[int, int](256)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 1; i <= 200; i++ {
+		l.Add(i, i*2)
+	}
+
+	expected := 200
+	for ent := range l.Stream(context.Background()) {
+		if ent.Key != expected || ent.Value != expected*2 {
+			t.Fatalf("bad entry: %v", ent)
+		}
+		expected--
+	}
+	if expected != 0 {
+		t.Fatalf("missing entries: %v", expected)
+	}
+	if keys := l.Keys(); keys[0] != 1 {
+		t.Fatalf("should not update recent-ness: %v", keys)
+	}
+}
+
+func TestCacheStream_Cancel(t *testing.T) {
+	l, err := New[int, int](256)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 1; i <= 200; i++ {
+		l.Add(i, i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := l.Stream(ctx)
+	<-ch
+	cancel()
+	n := 0
+	for range ch {
+		n++
+	}
+	if n == 199 {
+		t.Fatalf("should stop once cancelled")
+	}
+
+	// the cache stays usable, with no lock held by the stream
+	l.Add(300, 300)
+}
+
+func TestCacheStream_CursorRemoved(t *testing.T) {
+	l, err := New[int, int](256)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 1; i <= 200; i++ {
+		l.Add(i, i)
+	}
+
+	// remove the last entry of the first batch while it is being sent
+	ch := l.Stream(context.Background())
+	seen := make(map[int]bool)
+	for ent := range ch {
+		if ent.Key == 200 {
+			l.Remove(200 - streamBatchSize + 1)
+		}
+		seen[ent.Key] = true
+	}
+	for i := 1; i <= 200-streamBatchSize; i++ {
+		if !seen[i] {
+			t.Fatalf("missing entry: %v", i)
+		}
+	}
+}
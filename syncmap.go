@@ -0,0 +1,81 @@
+package lru
+
+import "github.com/errorhandler/golang-lru/simplelru"
+
+// SyncMapLRU is a bounded drop-in replacement for sync.Map, exposing the
+// same methods over a Cache so that code using sync.Map can be migrated with
+// minimal changes.
+//
+// Unlike sync.Map, SyncMapLRU holds at most the number of entries it was
+// created with: storing a new key in a full map evicts the least recently
+// used entry, so a key that was stored may later fail to load. Load counts as
+// a use of the key, while LoadOrStore and Range do not. Range iterates over a
+// snapshot of the entries, from oldest to newest, so f may call any method
+// of the map.
+type SyncMapLRU[Key comparable, Value any] struct {
+	c *Cache[Key, Value]
+}
+
+// NewSyncMapLRU creates a SyncMapLRU holding at most size entries.
+func NewSyncMapLRU[Key comparable, Value any](size int) (*SyncMapLRU[Key, Value], error) {
+	c, err := New[Key, Value](size)
+	if err != nil {
+		return nil, err
+	}
+	return &SyncMapLRU[Key, Value]{c: c}, nil
+}
+
+// Load returns the value stored in the map for a key, or the zero value if
+// no value is present. The ok result indicates whether value was found in
+// the map.
+func (m *SyncMapLRU[Key, Value]) Load(key Key) (value Value, ok bool) {
+	return m.c.Get(key)
+}
+
+// Store sets the value for a key, evicting the least recently used entry if
+// the map is full.
+func (m *SyncMapLRU[Key, Value]) Store(key Key, value Value) {
+	m.c.Add(key, value)
+}
+
+// LoadOrStore returns the existing value for the key if present. Otherwise,
+// it stores and returns the given value. The loaded result is true if the
+// value was loaded, false if stored.
+func (m *SyncMapLRU[Key, Value]) LoadOrStore(key Key, value Value) (actual Value, loaded bool) {
+	previous, loaded, _ := m.c.PeekOrAdd(key, value)
+	if loaded {
+		return previous, true
+	}
+	return value, false
+}
+
+// LoadAndDelete deletes the value for a key, returning the previous value if
+// any. The loaded result reports whether the key was present.
+func (m *SyncMapLRU[Key, Value]) LoadAndDelete(key Key) (value Value, loaded bool) {
+	return m.c.remove(key)
+}
+
+// Delete deletes the value for a key.
+func (m *SyncMapLRU[Key, Value]) Delete(key Key) {
+	m.c.Remove(key)
+}
+
+// Range calls f sequentially for each key and value present in the map, from
+// oldest to newest. If f returns false, Range stops the iteration.
+func (m *SyncMapLRU[Key, Value]) Range(f func(key Key, value Value) bool) {
+	var entries []simplelru.Entry[Key, Value]
+	m.c.ReadOnly().Range(func(key Key, value Value) bool {
+		entries = append(entries, simplelru.Entry[Key, Value]{Key: key, Value: value})
+		return true
+	})
+	for _, ent := range entries {
+		if !f(ent.Key, ent.Value) {
+			return
+		}
+	}
+}
+
+// Len returns the number of entries in the map.
+func (m *SyncMapLRU[Key, Value]) Len() int {
+	return m.c.Len()
+}
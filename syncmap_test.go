@@ -0,0 +1,65 @@
+package lru
+
+import "testing"
+
+func TestSyncMapLRU(t *testing.T) {
+	m, err := NewSyncMapLRU[int, int](2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	m.Store(1, 1)
+	if v, ok := m.Load(1); !ok || v != 1 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	if v, loaded := m.LoadOrStore(1, 10); !loaded || v != 1 {
+		t.Fatalf("bad: %v %v", v, loaded)
+	}
+	if v, loaded := m.LoadOrStore(2, 2); loaded || v != 2 {
+		t.Fatalf("bad: %v %v", v, loaded)
+	}
+
+	// bounded: storing a third key evicts the least recently used one
+	m.Store(3, 3)
+	if _, ok := m.Load(1); ok {
+		t.Fatalf("1 should be evicted")
+	}
+	if m.Len() != 2 {
+		t.Fatalf("bad len: %v", m.Len())
+	}
+
+	if v, loaded := m.LoadAndDelete(2); !loaded || v != 2 {
+		t.Fatalf("bad: %v %v", v, loaded)
+	}
+	if _, loaded := m.LoadAndDelete(2); loaded {
+		t.Fatalf("should not be loaded")
+	}
+	m.Delete(3)
+	if m.Len() != 0 {
+		t.Fatalf("bad len: %v", m.Len())
+	}
+}
+
+func TestSyncMapLRU_Range(t *testing.T) {
+	m, err := NewSyncMapLRU[int, int](4)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		m.Store(i, i)
+	}
+
+	// f may modify the map
+	var keys []int
+	m.Range(func(k, v int) bool {
+		keys = append(keys, k)
+		m.Delete(k)
+		return k < 2
+	})
+	if len(keys) != 3 || keys[0] != 0 || keys[2] != 2 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	if m.Len() != 1 {
+		t.Fatalf("bad len: %v", m.Len())
+	}
+}
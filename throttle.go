@@ -0,0 +1,51 @@
+package lru
+
+import (
+	"fmt"
+	"time"
+)
+
+// WithWriteThrottle limits the side effects of Adds that update an existing
+// key more often than once per minInterval, e.g. to protect the cache from a
+// client updating a single key in a tight loop. An Add updating a key less
+// than minInterval after the last unthrottled Add of that key still stores
+// the new value, completes a pending entry reserved with AddPending, and
+// bumps the generation of WithGenerations, but does not promote the key and
+// does not call the hook of WithCandidateChangeHook. Adds of new keys are
+// never throttled. Time is read from the clock set with WithClock.
+func WithWriteThrottle[Key comparable, Value any](minInterval time.Duration) Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		if minInterval <= 0 {
+			return fmt.Errorf("must provide a positive write throttle interval")
+		}
+		c.writeThrottle = &writeThrottle[Key]{
+			minInterval: minInterval,
+			last:        make(map[Key]time.Time),
+		}
+		return nil
+	}
+}
+
+// writeThrottle holds the time of the last unthrottled Add of each key.
+type writeThrottle[Key comparable] struct {
+	minInterval time.Duration
+	last        map[Key]time.Time
+}
+
+// throttleAdd updates the value of an existing key in place if its side
+// effects are throttled, returning whether it did. The caller must hold the
+// lock.
+func (c *Cache[Key, Value]) throttleAdd(key Key, value Value) bool {
+	if c.writeThrottle == nil || !c.lru.Contains(key) {
+		return false
+	}
+	last, ok := c.writeThrottle.last[key]
+	if !ok || c.now().Sub(last) >= c.writeThrottle.minInterval {
+		return false
+	}
+	c.replaceValue(key, value)
+	if p, ok := c.pending[key]; ok {
+		c.resolvePending(key, p, value)
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithWriteThrottle(t *testing.T) {
+	now := time.Unix(1000, 0)
+	clock := func() time.Time { return now }
+	var candidates []int
+	l, err := NewWithOptions(2, nil,
+		WithWriteThrottle[int, int](time.Second),
+		WithCandidateChangeHook[int, int](func(_, k int) {
+			candidates = append(candidates, k)
+		}),
+		WithClock[int, int](clock))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add(1, 1)
+	l.Add(2, 2)
+
+	// a quick update stores the value but does not promote the key
+	now = now.Add(time.Millisecond)
+	l.Add(1, 10)
+	if v, ok := l.Peek(1); !ok || v != 10 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	if k, _, _ := l.GetOldest(); k != 1 {
+		t.Fatalf("1 should not be promoted")
+	}
+
+	// an update after the interval is not throttled
+	now = now.Add(time.Second)
+	l.Add(1, 11)
+	if v, ok := l.Peek(1); !ok || v != 11 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	if k, _, _ := l.GetOldest(); k != 2 {
+		t.Fatalf("1 should be promoted")
+	}
+	if len(candidates) != 2 || candidates[1] != 2 {
+		t.Fatalf("bad candidates: %v", candidates)
+	}
+
+	// new keys are never throttled, and evicted keys are forgotten
+	l.Add(3, 3)
+	if l.Contains(2) {
+		t.Fatalf("2 should be evicted")
+	}
+	l.Add(2, 2)
+	if k, _, _ := l.GetOldest(); k != 3 {
+		t.Fatalf("bad oldest: %v", k)
+	}
+	if len(l.writeThrottle.last) != 2 {
+		t.Fatalf("bad throttle state: %v", l.writeThrottle.last)
+	}
+}
+
+func TestWithWriteThrottle_Invalid(t *testing.T) {
+	if _, err := NewWithOptions[int, int](2, nil, WithWriteThrottle[int, int](0)); err == nil {
+		t.Fatalf("should fail with non-positive interval")
+	}
+}
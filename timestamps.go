@@ -0,0 +1,65 @@
+package lru
+
+import "time"
+
+// WithTimestamps makes the cache record, for each entry, the time it was
+// first added and the time its value was last set, as reported by the clock
+// of the cache, so that Timestamps can tell how long an entry has been
+// cached and when it last changed. Updating the value of a key keeps its
+// added time; a key that is removed or evicted and then added again starts
+// over. This costs a map entry holding two time.Time values per cached key.
+func WithTimestamps[Key comparable, Value any]() Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		c.timestamps = &timestamps[Key]{
+			times: make(map[Key]entryTimes),
+		}
+		return nil
+	}
+}
+
+// timestamps holds the added and updated times of each entry.
+type timestamps[Key comparable] struct {
+	times map[Key]entryTimes
+}
+
+// entryTimes are the timestamps of a single entry.
+type entryTimes struct {
+	added, updated time.Time
+}
+
+// stamp records that the value of the key was set at now, which is also the
+// time it was added if it is new.
+func (t *timestamps[Key]) stamp(key Key, now time.Time) {
+	times, ok := t.times[key]
+	if !ok {
+		times.added = now
+	}
+	times.updated = now
+	t.times[key] = times
+}
+
+// evict drops the timestamps of a key that left the cache.
+func (t *timestamps[Key]) evict(key Key) {
+	delete(t.times, key)
+}
+
+// Timestamps returns the time the key was first added to the cache and the
+// time its value was last set, without updating the recent-ness of the key.
+// Reads do not change either time. A pending entry reserved with AddPending
+// is reported as missing; once filled, its added time is the time it was
+// reserved.
+//
+// The cache must be created with WithTimestamps, otherwise ok is always
+// false.
+func (c *Cache[Key, Value]) Timestamps(key Key) (added, updated time.Time, ok bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.timestamps == nil {
+		return
+	}
+	if _, pending := c.pending[key]; pending {
+		return
+	}
+	times, ok := c.timestamps.times[key]
+	return times.added, times.updated, ok
+}
@@ -0,0 +1,65 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithTimestamps(t *testing.T) {
+	now := time.Unix(1000, 0)
+	clock := func() time.Time { return now }
+	l, err := NewWithOptions(2, nil, WithClock[int, int](clock), WithTimestamps[int, int]())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add(1, 1)
+	now = now.Add(time.Second)
+	l.Add(1, 2)
+	now = now.Add(time.Second)
+	l.Get(1)
+	added, updated, ok := l.Timestamps(1)
+	if !ok {
+		t.Fatalf("should have timestamps")
+	}
+	if !added.Equal(time.Unix(1000, 0)) || !updated.Equal(time.Unix(1001, 0)) {
+		t.Fatalf("bad timestamps: %v %v", added, updated)
+	}
+
+	// values replaced in place are updates too
+	l.UpdateAll(func(k, v int) (int, bool) { return v + 1, true })
+	if added, updated, _ = l.Timestamps(1); !added.Equal(time.Unix(1000, 0)) || !updated.Equal(now) {
+		t.Fatalf("bad timestamps: %v %v", added, updated)
+	}
+
+	// re-adding a removed key starts over
+	l.Remove(1)
+	if _, _, ok := l.Timestamps(1); ok {
+		t.Fatalf("should not have timestamps")
+	}
+	l.Add(1, 1)
+	if added, updated, _ = l.Timestamps(1); !added.Equal(now) || !updated.Equal(now) {
+		t.Fatalf("bad timestamps: %v %v", added, updated)
+	}
+
+	// evicted keys are dropped
+	l.Add(2, 2)
+	l.Add(3, 3)
+	if _, _, ok := l.Timestamps(1); ok {
+		t.Fatalf("should not have timestamps")
+	}
+	if len(l.timestamps.times) != 2 {
+		t.Fatalf("bad len: %v", len(l.timestamps.times))
+	}
+}
+
+func TestTimestamps_Disabled(t *testing.T) {
+	l, err := New[int, int](2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, 1)
+	if _, _, ok := l.Timestamps(1); ok {
+		t.Fatalf("should not have timestamps")
+	}
+}
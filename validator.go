@@ -0,0 +1,30 @@
+package lru
+
+// WithGetValidator makes Get, GetTimeout and Query check each value they
+// find with valid, e.g. to detect leased resources such as connections that
+// became unusable out-of-band. An entry whose value is not valid is removed
+// from the cache, firing the eviction callback, and reported as a miss so
+// that the caller rebuilds it. Peek, Contains and the other methods do not
+// validate values.
+//
+// valid runs inside the critical section of the lookup, so it must be fast,
+// free of side effects, and must not call methods of the cache.
+func WithGetValidator[Key comparable, Value any](valid func(Value) bool) Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		c.getValidator = valid
+		return nil
+	}
+}
+
+// validate removes the entry of a key found by get if its value is not
+// valid, returning whether it was kept. The caller must hold the lock.
+func (c *Cache[Key, Value]) validate(key Key, value Value) bool {
+	if c.getValidator == nil || c.pending[key] != nil || c.getValidator(value) {
+		return true
+	}
+	c.lru.Remove(key)
+	c.admitStaged()
+	c.storeLen()
+	c.checkCandidate()
+	return false
+}
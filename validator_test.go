@@ -0,0 +1,51 @@
+package lru
+
+import "testing"
+
+func TestWithGetValidator(t *testing.T) {
+	var evicted []int
+	valid := func(v int) bool { return v >= 0 }
+	l, err := NewWithOptions(4, func(k, v int) {
+		evicted = append(evicted, k)
+	}, WithGetValidator[int, int](valid))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add(1, 1)
+	l.Add(2, -1)
+	l.Add(3, -1)
+	l.Add(4, -1)
+
+	// Peek and Contains do not validate
+	if v, ok := l.Peek(2); !ok || v != -1 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+	if v, ok := l.Get(1); !ok || v != 1 {
+		t.Fatalf("bad: %v %v", v, ok)
+	}
+
+	if _, ok := l.Get(2); ok {
+		t.Fatalf("invalid entry should miss")
+	}
+	if l.Contains(2) {
+		t.Fatalf("invalid entry should be evicted")
+	}
+	if len(evicted) != 1 || evicted[0] != 2 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	if _, ok := l.Get(2); ok {
+		t.Fatalf("should miss")
+	}
+
+	if _, ok := l.GetTimeout(3, 0); ok {
+		t.Fatalf("invalid entry should miss")
+	}
+	res := l.Query([]int{1, 4})
+	if len(res.Found) != 1 || len(res.Missing) != 1 || res.Missing[0] != 4 {
+		t.Fatalf("bad result: %v", res)
+	}
+	if len(evicted) != 3 || l.Len() != 1 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+}
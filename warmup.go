@@ -0,0 +1,64 @@
+package lru
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// WithWarmup lets the cache hold up to margin entries more than its size
+// for the given duration after it is created, so that an initial burst of
+// Adds while the cache fills up for the first time does not evict entries
+// that are about to be used again. Once the duration has passed, the first
+// Add, or other adding method, or the first Get, Peek, Contains, Keys, Len
+// or Stats trims the cache back to its size, evicting the least recently
+// used entries as Resize does. These methods read the clock on every call
+// until then. Resizing the cache during the warmup ends it, keeping the
+// new size. Time is read from the clock set with WithClock.
+//
+// Stats reports the size as Cap during the warmup, while the saturation
+// hook and WithOverflowBuffer only consider the cache full once it holds
+// margin entries more than its size.
+func WithWarmup[Key comparable, Value any](margin int, duration time.Duration) Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		if margin < 0 {
+			return fmt.Errorf("must provide a non-negative warmup margin")
+		}
+		if duration <= 0 {
+			return fmt.Errorf("must provide a positive warmup duration")
+		}
+		c.warmup = &warmup{
+			margin:   margin,
+			duration: duration,
+		}
+		return nil
+	}
+}
+
+// warmup holds the state of WithWarmup.
+type warmup struct {
+	margin   int
+	duration time.Duration
+	// size and until are set once the cache is created.
+	size  int
+	until time.Time
+	// done is set atomically once the warmup ended.
+	done int32
+}
+
+// warmingUp reports whether the warmup has not ended yet.
+func (c *Cache[Key, Value]) warmingUp() bool {
+	return c.warmup != nil && atomic.LoadInt32(&c.warmup.done) == 0
+}
+
+// endWarmup trims the cache back to its size once the warmup has passed.
+// It must be called without holding the lock.
+func (c *Cache[Key, Value]) endWarmup() {
+	w := c.warmup
+	if !c.warmingUp() || c.now().Before(w.until) {
+		return
+	}
+	if atomic.CompareAndSwapInt32(&w.done, 0, 1) {
+		c.Resize(w.size)
+	}
+}
@@ -0,0 +1,97 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithWarmup(t *testing.T) {
+	now := time.Unix(1000, 0)
+	clock := func() time.Time { return now }
+	var evicted []int
+	l, err := NewWithOptions(4, func(k, v int) {
+		evicted = append(evicted, k)
+	}, WithWarmup[int, int](2, time.Minute), WithClock[int, int](clock))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// the cache overshoots its size during the warmup
+	for i := 0; i < 7; i++ {
+		l.Add(i, i)
+	}
+	if l.Len() != 6 || len(evicted) != 1 || evicted[0] != 0 {
+		t.Fatalf("bad len: %v %v", l.Len(), evicted)
+	}
+
+	// and is trimmed back once it passed
+	now = now.Add(time.Minute)
+	l.Add(7, 7)
+	if l.Len() != 4 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+	if keys := l.Keys(); keys[0] != 4 || keys[3] != 7 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	if len(evicted) != 4 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	l.Add(8, 8)
+	if l.Len() != 4 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+}
+
+func TestWithWarmup_Resize(t *testing.T) {
+	now := time.Unix(1000, 0)
+	clock := func() time.Time { return now }
+	l, err := NewWithOptions(4, nil, WithWarmup[int, int](2, time.Minute), WithClock[int, int](clock))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Resize(8)
+	for i := 0; i < 8; i++ {
+		l.Add(i, i)
+	}
+	now = now.Add(time.Minute)
+	l.Add(8, 8)
+	if l.Len() != 8 {
+		t.Fatalf("resize should end the warmup: %v", l.Len())
+	}
+}
+
+func TestWithWarmup_Read(t *testing.T) {
+	now := time.Unix(1000, 0)
+	clock := func() time.Time { return now }
+	l, err := NewWithOptions(4, nil, WithWarmup[int, int](2, time.Minute), WithClock[int, int](clock))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 6; i++ {
+		l.Add(i, i)
+	}
+	if s := l.Stats(); s.Len != 6 || s.Cap != 4 {
+		t.Fatalf("bad stats: %v", s)
+	}
+
+	// a read is enough to trim the cache once the warmup passed
+	now = now.Add(time.Minute)
+	if _, ok := l.Peek(0); ok {
+		t.Fatalf("should be trimmed")
+	}
+	if s := l.Stats(); s.Len != 4 || s.Cap != 4 {
+		t.Fatalf("bad stats: %v", s)
+	}
+}
+
+func TestWithWarmup_Invalid(t *testing.T) {
+	if _, err := NewWithOptions[int, int](4, nil, WithWarmup[int, int](-1, time.Minute)); err == nil {
+		t.Fatalf("should fail with negative margin")
+	}
+	if _, err := NewWithOptions[int, int](4, nil, WithWarmup[int, int](1, 0)); err == nil {
+		t.Fatalf("should fail with non-positive duration")
+	}
+	if _, err := NewWithOptions[int, int](0, nil, WithWarmup[int, int](1, time.Minute)); err == nil {
+		t.Fatalf("should fail with invalid size")
+	}
+}
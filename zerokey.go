@@ -0,0 +1,30 @@
+package lru
+
+import "errors"
+
+// ErrZeroKey is returned by TryAdd for the zero key when the cache is
+// created with WithRejectZeroKey.
+var ErrZeroKey = errors.New("zero key rejected")
+
+// WithRejectZeroKey makes the cache reject the zero value of its key type,
+// such as the empty string or 0, for caches where adding it is always a
+// bug, e.g. a key left unset, rather than a meaningful key. The key is
+// compared with the zero value using ==, which Key supports by being
+// comparable; for struct keys, only a key whose fields are all zero is
+// rejected. Rejected keys are simply not cached: Add, ContainsOrAdd and
+// PeekOrAdd store nothing and report no eviction, AddPending reserves
+// nothing, and TryAdd returns ErrZeroKey. By default, the zero key is
+// cached like any other key.
+func WithRejectZeroKey[Key comparable, Value any]() Option[Key, Value] {
+	return func(c *Cache[Key, Value]) error {
+		c.rejectZeroKey = true
+		return nil
+	}
+}
+
+// zeroKeyRejected reports whether the key must be rejected for being the
+// zero key.
+func (c *Cache[Key, Value]) zeroKeyRejected(key Key) bool {
+	var zeroKey Key
+	return c.rejectZeroKey && key == zeroKey
+}
@@ -0,0 +1,40 @@
+package lru
+
+import "testing"
+
+func TestWithRejectZeroKey(t *testing.T) {
+	l, err := NewWithOptions[string, int](4, nil, WithRejectZeroKey[string, int]())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add("", 1)
+	l.Add("a", 2)
+	if l.Contains("") || !l.Contains("a") {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+	if ok, _ := l.ContainsOrAdd("", 1); ok || l.Contains("") {
+		t.Fatalf("should reject zero key")
+	}
+	fill, already := l.AddPending("")
+	fill(1)
+	if already || l.Contains("") {
+		t.Fatalf("should reject zero key")
+	}
+	if _, err := l.TryAdd("", 1); err != ErrZeroKey {
+		t.Fatalf("bad err: %v", err)
+	}
+	if l.Len() != 1 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+
+	// zero keys are allowed by default
+	l, err = New[string, int](4)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add("", 1)
+	if !l.Contains("") {
+		t.Fatalf("should cache zero key")
+	}
+}